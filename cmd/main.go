@@ -1,18 +1,49 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/cache"
 	"gojek/library-service-api/internal/config"
 	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/eventbus"
+	"gojek/library-service-api/internal/graph"
+	"gojek/library-service-api/internal/grpcserver"
+	"gojek/library-service-api/internal/idempotency"
+	"gojek/library-service-api/internal/logger"
+	"gojek/library-service-api/internal/metrics"
+	"gojek/library-service-api/internal/middleware"
+	"gojek/library-service-api/internal/outbox"
+	"gojek/library-service-api/internal/ratelimit"
 	"gojek/library-service-api/internal/repository"
-	"log"
+	"gojek/library-service-api/internal/router"
+	"gojek/library-service-api/internal/server"
+	"gojek/library-service-api/internal/sse"
+	"gojek/library-service-api/internal/tracing"
+	"gojek/library-service-api/internal/webhook"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"time"
 
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
-	port := config.GetEnv("PORT", "8080")
+	serverConfig := config.NewServerConfig()
+
+	logConfig := config.NewLogConfig()
+	slog.SetDefault(logger.New(os.Stdout, logConfig.Level, logConfig.Format))
+
+	tracingConfig := config.NewTracingConfig()
+	if tracingConfig.Exporter == "stdout" {
+		tracing.SetTracer(&tracing.Tracer{Exporter: &tracing.StdoutExporter{Writer: os.Stdout}})
+	}
 
 	dbConfig := config.NewDBConfig()
 
@@ -23,38 +54,187 @@ func main() {
 
 	db, err := sql.Open("postgres", dbConnection)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("failed to open database", "error", err)
+		os.Exit(1)
 	}
 	defer db.Close()
 
-	bookRepository := &repository.BookRepository{DB: db}
-	bookController := &controller.BookController{Repository: bookRepository}
-
-	http.HandleFunc("/ping", controller.HandlePingRequest)
-	http.HandleFunc("/healthz", controller.HandleHealthCheckRequest)
-	http.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			bookController.GetAllBooks(w, r)
-		case http.MethodPost:
-			bookController.AddBook(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	cacheConfig := config.NewCacheConfig()
+	bookRepository := &repository.BookRepository{DB: db, QueryTimeout: dbConfig.QueryTimeout, StreamTimeout: dbConfig.StreamTimeout}
+	bookStore := &repository.CachedBookStore{BookStore: bookRepository}
+	var bookCacheStats interface{ Stats() cache.Stats }
+	switch {
+	case cacheConfig.BookCacheBackend == "memory":
+		memoryCache := cache.NewMemoryCache(cacheConfig.BookCacheSize, cacheConfig.BookCacheTTL)
+		bookStore.Cache, bookCacheStats = memoryCache, memoryCache
+	case cacheConfig.BookCacheBackend == "redis" && cacheConfig.RedisAddr != "":
+		redisCache := cache.NewRedisCache(cacheConfig.RedisAddr, cacheConfig.RedisPassword, cacheConfig.BookCacheTTL)
+		bookStore.Cache, bookCacheStats = redisCache, redisCache
+	default:
+		slog.Error("invalid book cache backend; use memory, or redis with REDIS_ADDR set", "backend", cacheConfig.BookCacheBackend)
+		os.Exit(1)
+	}
+	eventStreamConfig := config.NewEventStreamConfig()
+	bookEvents := sse.NewBroker(eventStreamConfig.ReplaySize)
+	bookChanges := &eventbus.Bus{}
+	bookChanges.Subscribe(bookStore)
+	go bookStore.Run(context.Background())
+	bookChanges.Subscribe(bookEvents)
+	bookChangeListener := &repository.BookChangeListener{ConnInfo: dbConnection, Bus: bookChanges,
+		MinReconnectInterval: dbConfig.ListenerMinReconnect, MaxReconnectInterval: dbConfig.ListenerMaxReconnect}
+	go func() {
+		if err := bookChangeListener.Run(context.Background()); err != nil {
+			slog.Error("failed to listen for book changes", "channel", repository.BookChangesChannel, "error", err)
 		}
-	})
-	http.HandleFunc("/books/", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			bookController.GetBookByID(w, r)
-		case http.MethodPut:
-			bookController.UpdateBookTitle(w, r)
-		case http.MethodDelete:
-			bookController.DeleteBookByID(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}()
+
+	webhookConfig := config.NewWebhookConfig()
+	webhookStore := &repository.WebhookRepository{DB: db}
+	webhookDispatcher := &webhook.Dispatcher{
+		Store:       webhookStore,
+		Client:      &http.Client{Timeout: webhookConfig.Timeout},
+		MaxAttempts: webhookConfig.MaxAttempts,
+		BaseDelay:   webhookConfig.RetryBaseDelay,
+		MaxDelay:    webhookConfig.RetryMaxDelay,
+		BatchSize:   webhookConfig.BatchSize,
+		Interval:    webhookConfig.PollInterval,
+	}
+	go webhookDispatcher.Run(context.Background())
+
+	outboxConfig := config.NewOutboxConfig()
+	publishers := outbox.Publishers{webhookDispatcher}
+	switch {
+	case outboxConfig.Publisher == "log":
+		publishers = append(publishers, &outbox.LogPublisher{})
+	case outboxConfig.Publisher == "webhook" && outboxConfig.WebhookURL != "":
+		publishers = append(publishers, &outbox.WebhookPublisher{URL: outboxConfig.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}})
+	case outboxConfig.Publisher == "redis" && cacheConfig.RedisAddr != "":
+		publishers = append(publishers, &outbox.PubSubPublisher{
+			PubSub:  cache.NewRedisCache(cacheConfig.RedisAddr, cacheConfig.RedisPassword, 0),
+			Channel: outboxConfig.Channel,
+		})
+	case outboxConfig.Publisher == "none":
+	default:
+		slog.Error("invalid outbox publisher; use log, webhook with OUTBOX_WEBHOOK_URL, redis with REDIS_ADDR, or none",
+			"publisher", outboxConfig.Publisher)
+		os.Exit(1)
+	}
+	relay := &outbox.Relay{Store: &repository.OutboxRepository{DB: db}, Publisher: publishers,
+		BatchSize: outboxConfig.BatchSize, Interval: outboxConfig.PollInterval}
+	go relay.Run(context.Background())
+
+	bookController := &controller.BookController{Repository: bookStore, CacheMaxAge: cacheConfig.HTTPMaxAge}
+	bookControllerV2 := &controller.BookControllerV2{Repository: bookStore, CacheMaxAge: cacheConfig.HTTPMaxAge}
+
+	authConfig := config.NewAuthConfig()
+	authenticator := &auth.Authenticator{APIKeys: &repository.APIKeyRepository{DB: db}}
+	if authConfig.JWKSFile != "" {
+		keySet, err := auth.LoadJWKS(authConfig.JWKSFile)
+		if err != nil {
+			slog.Error("failed to load jwks", "error", err)
+			os.Exit(1)
 		}
+		authenticator.JWTVerifier = &auth.JWTVerifier{Keys: keySet, Issuer: authConfig.JWTIssuer, Audience: authConfig.JWTAudience}
+	}
+
+	policy := authz.DefaultPolicy()
+	if authConfig.PolicyFile != "" {
+		policy, err = authz.LoadPolicy(authConfig.PolicyFile)
+		if err != nil {
+			slog.Error("failed to load authorization policy", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	graphQLSchema, err := graph.NewSchema(&graph.Resolver{Repository: bookStore, Policy: policy})
+	if err != nil {
+		slog.Error("failed to parse graphql schema", "error", err)
+		os.Exit(1)
+	}
+	graphQLController := &controller.GraphQLController{Schema: graphQLSchema, Repository: bookStore}
+
+	rateLimitConfig := config.NewRateLimitConfig()
+	defaultLimit, err := ratelimit.ParseLimit(rateLimitConfig.Default)
+	if err != nil {
+		slog.Error("invalid default rate limit", "error", err)
+		os.Exit(1)
+	}
+	routeLimits := map[string]ratelimit.Limit{}
+	for route, value := range rateLimitConfig.Routes {
+		if routeLimits[route], err = ratelimit.ParseLimit(value); err != nil {
+			slog.Error("invalid route rate limit", "route", route, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	registry := metrics.NewRegistry()
+	registry.MustRegister(repository.QueryDuration, outbox.PublishAttempts, &metrics.DBStatsCollector{DB: db},
+		&metrics.CacheStatsCollector{Name: "books", Cache: bookCacheStats})
+
+	appRouter := router.New(
+		middleware.RequestID,
+		middleware.AccessLog,
+		middleware.Metrics(registry),
+		middleware.Tracing,
+		middleware.Recover,
+		middleware.SecurityHeaders(config.NewSecurityHeadersConfig()),
+		middleware.CORS(config.NewCORSConfig()),
+		middleware.BodyLimit(serverConfig.MaxBodyBytes),
+		middleware.Compress(serverConfig.CompressionMinBytes),
+	)
+
+	registerRoutes(appRouter, routeDependencies{
+		BookController:   bookController,
+		BookControllerV2: bookControllerV2,
+		GraphQL:          graphQLController,
+		BookEvents:       &controller.BookEventsController{Broker: bookEvents, HeartbeatInterval: eventStreamConfig.HeartbeatInterval},
+		Metrics:          registry.Handler(),
+		Authenticate:     middleware.Authenticate(authenticator, authConfig.PublicReads),
+		RateLimit:        middleware.RateLimit(ratelimit.NewMemoryStore(), routeLimits, defaultLimit),
+		Policy:           policy,
+		Idempotency:      middleware.Idempotency(idempotency.NewMemoryStore(), serverConfig.IdempotencyTTL),
+		APIVersions:      config.NewAPIVersionConfig(),
+		Webhooks:         &controller.WebhookController{Store: webhookStore},
 	})
 
-	log.Printf("Server started at port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	httpServer := &http.Server{Addr: ":" + serverConfig.Port, Handler: appRouter}
+	tlsConfig := config.NewTLSConfig()
+	if tlsConfig.Enabled() {
+		httpServer.TLSConfig, err = server.NewTLSConfig(tlsConfig)
+		if err != nil {
+			slog.Error("failed to configure tls", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	grpcConfig := config.NewGRPCConfig()
+	grpcOptions := []grpc.ServerOption{}
+	if tlsConfig.Enabled() {
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(httpServer.TLSConfig)))
+	}
+	grpcServer := grpcserver.NewServer(&grpcserver.BookService{Repository: bookStore},
+		authenticator, policy, authConfig.PublicReads, grpcOptions...)
+	grpcListener, err := net.Listen("tcp", ":"+grpcConfig.Port)
+	if err != nil {
+		slog.Error("failed to listen for grpc", "port", grpcConfig.Port, "error", err)
+		os.Exit(1)
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("grpc server stopped", "error", err)
+			os.Exit(1)
+		}
+	}()
+	slog.Info("grpc server started", "port", grpcConfig.Port, "tls", tlsConfig.Enabled())
+
+	slog.Info("server started", "port", serverConfig.Port, "tls", tlsConfig.Enabled(), "client_auth", tlsConfig.ClientAuth)
+	if tlsConfig.Enabled() {
+		err = httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }
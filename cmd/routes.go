@@ -0,0 +1,152 @@
+package main
+
+import (
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/config"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/middleware"
+	"gojek/library-service-api/internal/openapi"
+	"gojek/library-service-api/internal/router"
+	"net/http"
+)
+
+type routeDependencies struct {
+	BookController   *controller.BookController
+	BookControllerV2 *controller.BookControllerV2
+	GraphQL          *controller.GraphQLController
+	BookEvents       *controller.BookEventsController
+	Metrics          http.Handler
+	Authenticate     middleware.Middleware
+	RateLimit        middleware.Middleware
+	Policy           *authz.Policy
+	Idempotency      middleware.Middleware
+	APIVersions      config.APIVersionConfig
+	Webhooks         *controller.WebhookController
+}
+
+type bookHandlers struct {
+	GetAllBooks     http.HandlerFunc
+	AddBook         http.HandlerFunc
+	GetBookByID     http.HandlerFunc
+	UpdateBookTitle http.HandlerFunc
+	DeleteBookByID  http.HandlerFunc
+}
+
+// registerRoutes runs RateLimit after Authenticate on every route that
+// authenticates, so callers are limited per verified principal and only
+// anonymous ones per client IP.
+func registerRoutes(appRouter *router.Router, dependencies routeDependencies) {
+	appRouter.HandleFunc("/ping", controller.HandlePingRequest, dependencies.RateLimit)
+	appRouter.HandleFunc("/healthz", controller.HandleHealthCheckRequest, dependencies.RateLimit)
+	appRouter.HandleFunc("/metrics", dependencies.Metrics.ServeHTTP, dependencies.RateLimit)
+	appRouter.HandleFunc("/openapi.json", openapi.HandleDocumentRequest, dependencies.RateLimit)
+	appRouter.HandleFunc("/docs", openapi.HandleDocsRequest, dependencies.RateLimit)
+	appRouter.HandleFunc("/graphql", dependencies.GraphQL.HandleGraphQLRequest, middleware.AllowAnonymous, dependencies.Authenticate,
+		dependencies.RateLimit)
+
+	v1Handlers := bookHandlers{
+		GetAllBooks:     dependencies.BookController.GetAllBooks,
+		AddBook:         dependencies.BookController.AddBook,
+		GetBookByID:     dependencies.BookController.GetBookByID,
+		UpdateBookTitle: dependencies.BookController.UpdateBookTitle,
+		DeleteBookByID:  dependencies.BookController.DeleteBookByID,
+	}
+	v2Handlers := bookHandlers{
+		GetAllBooks:     dependencies.BookControllerV2.GetAllBooks,
+		AddBook:         dependencies.BookControllerV2.AddBook,
+		GetBookByID:     dependencies.BookControllerV2.GetBookByID,
+		UpdateBookTitle: dependencies.BookControllerV2.UpdateBookTitle,
+		DeleteBookByID:  dependencies.BookControllerV2.DeleteBookByID,
+	}
+	versions := dependencies.APIVersions
+
+	registerBookRoutes(appRouter, "", v1Handlers, dependencies,
+		middleware.Deprecation(versions.V1DeprecatedAt, versions.V1SunsetAt, "", "/v2"))
+	registerBookRoutes(appRouter, "/v1", v1Handlers, dependencies,
+		middleware.Deprecation(versions.V1DeprecatedAt, versions.V1SunsetAt, "/v1", "/v2"))
+	registerBookRoutes(appRouter, "/v2", v2Handlers, dependencies)
+	appRouter.HandleFunc("/books/events", onlyGet(dependencies.BookEvents.StreamBookEvents), dependencies.Authenticate,
+		dependencies.RateLimit, middleware.Authorize(dependencies.Policy, map[string]string{http.MethodGet: authz.PermissionReadBooks}))
+	registerWebhookRoutes(appRouter, dependencies)
+}
+
+// registerBookRoutes registers the books API under prefix, running
+// versionMiddlewares before content negotiation and authentication so that
+// every response of the version carries their headers.
+func registerBookRoutes(appRouter *router.Router, prefix string, handlers bookHandlers, dependencies routeDependencies, versionMiddlewares ...middleware.Middleware) {
+	collectionMiddlewares := append(append([]middleware.Middleware{}, versionMiddlewares...),
+		middleware.ContentNegotiation(), dependencies.Authenticate, dependencies.RateLimit, middleware.Authorize(dependencies.Policy, map[string]string{
+			http.MethodGet:  authz.PermissionReadBooks,
+			http.MethodPost: authz.PermissionWriteBooks,
+		}), dependencies.Idempotency)
+	appRouter.HandleFunc(prefix+"/books", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetAllBooks(w, r)
+		case http.MethodPost:
+			handlers.AddBook(w, r)
+		default:
+			controller.WriteMethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+		}
+	}, collectionMiddlewares...)
+
+	itemMiddlewares := append(append([]middleware.Middleware{}, versionMiddlewares...),
+		middleware.ContentNegotiation(), dependencies.Authenticate, dependencies.RateLimit, middleware.Authorize(dependencies.Policy, map[string]string{
+			http.MethodGet:    authz.PermissionReadBooks,
+			http.MethodPut:    authz.PermissionWriteBooks,
+			http.MethodDelete: authz.PermissionDeleteBooks,
+		}))
+	appRouter.HandleFunc(prefix+"/books/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetBookByID(w, r)
+		case http.MethodPut:
+			handlers.UpdateBookTitle(w, r)
+		case http.MethodDelete:
+			handlers.DeleteBookByID(w, r)
+		default:
+			controller.WriteMethodNotAllowed(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+		}
+	}, itemMiddlewares...)
+}
+
+func registerWebhookRoutes(appRouter *router.Router, dependencies routeDependencies) {
+	webhooks := dependencies.Webhooks
+	middlewares := []middleware.Middleware{dependencies.Authenticate, dependencies.RateLimit, middleware.Authorize(dependencies.Policy, map[string]string{
+		http.MethodGet:    authz.PermissionManageWebhooks,
+		http.MethodPost:   authz.PermissionManageWebhooks,
+		http.MethodDelete: authz.PermissionManageWebhooks,
+	})}
+	appRouter.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			webhooks.GetAllWebhooks(w, r)
+		case http.MethodPost:
+			webhooks.AddWebhook(w, r)
+		default:
+			controller.WriteMethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+		}
+	}, middlewares...)
+	appRouter.HandleFunc("/webhooks/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			webhooks.GetWebhookByID(w, r)
+		case http.MethodDelete:
+			webhooks.DeleteWebhookByID(w, r)
+		default:
+			controller.WriteMethodNotAllowed(w, r, http.MethodGet, http.MethodDelete)
+		}
+	}, middlewares...)
+	appRouter.HandleFunc("/webhooks/{id}/deliveries", onlyGet(webhooks.GetWebhookDeliveries), middlewares...)
+	appRouter.HandleFunc("/webhooks/{id}/dead-letters", onlyGet(webhooks.GetWebhookDeadLetters), middlewares...)
+}
+
+func onlyGet(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			controller.WriteMethodNotAllowed(w, r, http.MethodGet)
+			return
+		}
+		handler(w, r)
+	}
+}
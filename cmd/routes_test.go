@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/config"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/graph"
+	"gojek/library-service-api/internal/middleware"
+	"gojek/library-service-api/internal/openapi"
+	"gojek/library-service-api/internal/ratelimit"
+	"gojek/library-service-api/internal/repository"
+	"gojek/library-service-api/internal/router"
+	"gojek/library-service-api/internal/webhook"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter() *router.Router {
+	appRouter := router.New()
+	registerRoutes(appRouter, newTestRouteDependencies())
+	return appRouter
+}
+
+func newTestRouteDependencies() routeDependencies {
+	passThrough := func(next http.Handler) http.Handler { return next }
+	return routeDependencies{
+		BookController:   &controller.BookController{},
+		BookControllerV2: &controller.BookControllerV2{},
+		GraphQL:          &controller.GraphQLController{},
+		BookEvents:       &controller.BookEventsController{},
+		Metrics:          http.NotFoundHandler(),
+		Authenticate:     passThrough,
+		RateLimit:        passThrough,
+		Policy:           authz.DefaultPolicy(),
+		Idempotency:      passThrough,
+		APIVersions:      config.NewAPIVersionConfig(),
+		Webhooks:         &controller.WebhookController{},
+	}
+}
+
+func documentedPaths(t *testing.T) map[string]bool {
+	document := struct {
+		Paths map[string]interface{} `json:"paths"`
+	}{}
+	if err := json.Unmarshal(openapi.Document, &document); err != nil {
+		t.Fatalf("Failed to parse OpenAPI document: %v", err)
+	}
+	paths := map[string]bool{}
+	for path := range document.Paths {
+		paths[path] = true
+	}
+	return paths
+}
+
+func TestRegisterRoutes_GivenRegisteredRoutes_ThenEveryRouteIsDocumented(t *testing.T) {
+	paths := documentedPaths(t)
+
+	for _, route := range newTestRouter().Routes() {
+		assert.True(t, paths[openapi.Path(route)], "route %s is missing from openapi.json", route)
+	}
+}
+
+func TestRegisterRoutes_GivenDocumentedPaths_ThenEveryPathIsRegistered(t *testing.T) {
+	registered := map[string]bool{}
+	for _, route := range newTestRouter().Routes() {
+		registered[openapi.Path(route)] = true
+	}
+
+	for path := range documentedPaths(t) {
+		assert.True(t, registered[path], "documented path %s is not registered", path)
+	}
+}
+
+func TestRegisterRoutes_GivenVersionOneRoutes_ThenMarkResponsesDeprecated(t *testing.T) {
+	appRouter := newTestRouter()
+
+	for _, path := range []string{"/books", "/v1/books", "/v1/books/1"} {
+		w := httptest.NewRecorder()
+		appRouter.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, path, nil))
+
+		assert.NotEmpty(t, w.Header().Get("Deprecation"), path)
+		assert.NotEmpty(t, w.Header().Get("Sunset"), path)
+		assert.Contains(t, w.Header().Get("Link"), `rel="successor-version"`, path)
+	}
+}
+
+func TestRegisterRoutes_GivenVersionTwoRoutes_ThenNoDeprecationHeaders(t *testing.T) {
+	appRouter := newTestRouter()
+
+	for _, path := range []string{"/v2/books", "/v2/books/1"} {
+		w := httptest.NewRecorder()
+		appRouter.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, path, nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code, path)
+		assert.Empty(t, w.Header().Get("Deprecation"), path)
+	}
+}
+
+type apiKeysByHash map[string]domain.APIKey
+
+func (apiKeys apiKeysByHash) FindAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	apiKey, exists := apiKeys[keyHash]
+	if !exists {
+		return domain.APIKey{}, sql.ErrNoRows
+	}
+	return apiKey, nil
+}
+
+func TestRegisterRoutes_GivenTwoAPIKeysFromSameIP_ThenRateLimitEachKeySeparately(t *testing.T) {
+	dependencies := newTestRouteDependencies()
+	dependencies.Authenticate = middleware.Authenticate(&auth.Authenticator{APIKeys: apiKeysByHash{
+		auth.HashAPIKey("first-key"):  {Subject: "first", Roles: []string{"admin"}},
+		auth.HashAPIKey("second-key"): {Subject: "second", Roles: []string{"admin"}},
+	}}, false)
+	dependencies.RateLimit = middleware.RateLimit(ratelimit.NewMemoryStore(), nil, ratelimit.Limit{Rate: 1, Burst: 1})
+	dependencies.Webhooks = &controller.WebhookController{Store: webhook.NewMemoryStore()}
+	appRouter := router.New()
+	registerRoutes(appRouter, dependencies)
+
+	statuses := []int{}
+	for _, apiKey := range []string{"first-key", "second-key", "first-key"} {
+		req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(auth.APIKeyHeader, apiKey)
+		w := httptest.NewRecorder()
+		appRouter.ServeHTTP(w, req)
+		statuses = append(statuses, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses)
+}
+
+func TestRegisterRoutes_GivenAnonymousGraphQLQueryByPostAndPublicReads_ThenExecuteIt(t *testing.T) {
+	schema, err := graph.NewSchema(&graph.Resolver{Repository: &repository.BookRepository{}, Policy: authz.DefaultPolicy()})
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	dependencies := newTestRouteDependencies()
+	dependencies.Authenticate = middleware.Authenticate(&auth.Authenticator{}, true)
+	dependencies.GraphQL = &controller.GraphQLController{Schema: schema, Repository: &repository.BookRepository{}}
+	appRouter := router.New()
+	registerRoutes(appRouter, dependencies)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ __typename }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	appRouter.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"__typename":"Query"}}`, w.Body.String())
+}
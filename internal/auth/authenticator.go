@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"net/http"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+)
+
+type APIKeyFinder interface {
+	FindAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error)
+}
+
+type Authenticator struct {
+	JWTVerifier *JWTVerifier
+	APIKeys     APIKeyFinder
+}
+
+// Authenticate resolves the caller from a bearer JWT, an X-API-Key header or a
+// verified client certificate, in that order. It returns ErrMissingCredentials
+// when the request carries none of them.
+func (authenticator *Authenticator) Authenticate(r *http.Request) (Principal, error) {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		if authenticator.JWTVerifier == nil {
+			return Principal{}, ErrInvalidToken
+		}
+		return authenticator.JWTVerifier.Verify(strings.TrimSpace(token))
+	}
+
+	if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+		if authenticator.APIKeys == nil {
+			return Principal{}, ErrInvalidAPIKey
+		}
+		storedKey, err := authenticator.APIKeys.FindAPIKeyByHash(r.Context(), HashAPIKey(apiKey))
+		if errors.Is(err, sql.ErrNoRows) {
+			return Principal{}, ErrInvalidAPIKey
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+		return Principal{Subject: storedKey.Subject, Roles: storedKey.Roles, Method: "api_key"}, nil
+	}
+
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		return ClientCertificatePrincipal(r.TLS.VerifiedChains[0][0]), nil
+	}
+
+	return Principal{}, ErrMissingCredentials
+}
+
+// ClientCertificatePrincipal identifies a caller by a client certificate that
+// the TLS handshake already verified, taking roles from its organizational units.
+func ClientCertificatePrincipal(certificate *x509.Certificate) Principal {
+	return Principal{
+		Subject: certificate.Subject.CommonName,
+		Roles:   append([]string{}, certificate.Subject.OrganizationalUnit...),
+		Method:  "mtls",
+	}
+}
+
+func HashAPIKey(apiKey string) string {
+	digest := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,57 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAPIKeyFinder struct {
+	apiKeys map[string]domain.APIKey
+}
+
+func (finder *fakeAPIKeyFinder) FindAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	apiKey, exists := finder.apiKeys[keyHash]
+	if !exists {
+		return domain.APIKey{}, sql.ErrNoRows
+	}
+	return apiKey, nil
+}
+
+func TestAuthenticate_GivenKnownAPIKey_ThenReturnPrincipal(t *testing.T) {
+	finder := &fakeAPIKeyFinder{apiKeys: map[string]domain.APIKey{
+		auth.HashAPIKey("secret-key"): {Subject: "catalogue-importer", Roles: []string{"librarian"}},
+	}}
+	authenticator := &auth.Authenticator{APIKeys: finder}
+
+	req := httptest.NewRequest(http.MethodPost, "/books", nil)
+	req.Header.Set(auth.APIKeyHeader, "secret-key")
+	principal, err := authenticator.Authenticate(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "catalogue-importer", Roles: []string{"librarian"}, Method: "api_key"}, principal)
+}
+
+func TestAuthenticate_GivenUnknownAPIKey_ThenReturnInvalidAPIKeyError(t *testing.T) {
+	authenticator := &auth.Authenticator{APIKeys: &fakeAPIKeyFinder{}}
+
+	req := httptest.NewRequest(http.MethodPost, "/books", nil)
+	req.Header.Set(auth.APIKeyHeader, "unknown-key")
+	_, err := authenticator.Authenticate(req)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+}
+
+func TestAuthenticate_GivenNoCredentials_ThenReturnMissingCredentialsError(t *testing.T) {
+	authenticator := &auth.Authenticator{APIKeys: &fakeAPIKeyFinder{}}
+
+	_, err := authenticator.Authenticate(httptest.NewRequest(http.MethodPost, "/books", nil))
+
+	assert.ErrorIs(t, err, auth.ErrMissingCredentials)
+}
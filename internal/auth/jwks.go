@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+type KeySet struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+}
+
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	K       string `json:"k"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+func LoadJWKS(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS reads symmetric ("oct") keys for HS256 and "RSA" public keys for
+// RS256 from a JSON Web Key Set document.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	document := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	keySet := &KeySet{hmacKeys: map[string][]byte{}, rsaKeys: map[string]*rsa.PublicKey{}}
+	for _, key := range document.Keys {
+		switch key.KeyType {
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return nil, fmt.Errorf("decode key %q: %w", key.KeyID, err)
+			}
+			keySet.hmacKeys[key.KeyID] = secret
+		case "RSA":
+			modulus, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return nil, fmt.Errorf("decode key %q modulus: %w", key.KeyID, err)
+			}
+			exponent, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return nil, fmt.Errorf("decode key %q exponent: %w", key.KeyID, err)
+			}
+			keySet.rsaKeys[key.KeyID] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(modulus),
+				E: int(new(big.Int).SetBytes(exponent).Int64()),
+			}
+		}
+	}
+	return keySet, nil
+}
@@ -0,0 +1,29 @@
+package auth_test
+
+import (
+	"gojek/library-service-api/internal/auth"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadJWKS_GivenMissingFile_ThenReturnError(t *testing.T) {
+	_, err := auth.LoadJWKS(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestLoadJWKS_GivenJWKSFile_ThenReturnKeySet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	os.WriteFile(path, []byte(`{"keys":[{"kid":"hmac-key","kty":"oct","k":"bGlicmFyeS1zZWNyZXQ"}]}`), 0o600)
+
+	keySet, err := auth.LoadJWKS(path)
+	assert.NoError(t, err)
+	assert.NotNil(t, keySet)
+}
+
+func TestParseJWKS_GivenInvalidKeyMaterial_ThenReturnError(t *testing.T) {
+	_, err := auth.ParseJWKS([]byte(`{"keys":[{"kid":"hmac-key","kty":"oct","k":"!!"}]}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type JWTVerifier struct {
+	Keys     *KeySet
+	Issuer   string
+	Audience string
+	Now      func() time.Time
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+	Roles     []string        `json:"roles"`
+}
+
+func (verifier *JWTVerifier) Verify(token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, ErrInvalidToken
+	}
+
+	header := struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+	if err := verifier.verifySignature(header.Algorithm, header.KeyID, parts[0]+"."+parts[1], signature); err != nil {
+		return Principal{}, err
+	}
+
+	claims := jwtClaims{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, ErrInvalidToken
+	}
+	if err := verifier.validateClaims(claims); err != nil {
+		return Principal{}, err
+	}
+	return Principal{Subject: claims.Subject, Roles: claims.Roles, Method: "jwt"}, nil
+}
+
+func (verifier *JWTVerifier) verifySignature(algorithm, keyID, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+	switch algorithm {
+	case "HS256":
+		secret, exists := verifier.Keys.hmacKeys[keyID]
+		if !exists {
+			return ErrInvalidToken
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidToken
+		}
+		return nil
+	case "RS256":
+		publicKey, exists := verifier.Keys.rsaKeys[keyID]
+		if !exists {
+			return ErrInvalidToken
+		}
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidToken
+		}
+		return nil
+	}
+	return ErrInvalidToken
+}
+
+func (verifier *JWTVerifier) validateClaims(claims jwtClaims) error {
+	now := time.Now()
+	if verifier.Now != nil {
+		now = verifier.Now()
+	}
+	if claims.Subject == "" || claims.ExpiresAt == nil || now.Unix() >= *claims.ExpiresAt {
+		return ErrInvalidToken
+	}
+	if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+		return ErrInvalidToken
+	}
+	if verifier.Issuer != "" && claims.Issuer != verifier.Issuer {
+		return ErrInvalidToken
+	}
+	if verifier.Audience != "" && !containsAudience(claims.Audience, verifier.Audience) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// containsAudience accepts the "aud" claim both as a single string and as an
+// array of strings, as allowed by RFC 7519.
+func containsAudience(rawAudience json.RawMessage, audience string) bool {
+	single := ""
+	if json.Unmarshal(rawAudience, &single) == nil {
+		return single == audience
+	}
+	multiple := []string{}
+	if json.Unmarshal(rawAudience, &multiple) == nil {
+		for _, value := range multiple {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
@@ -0,0 +1,127 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"gojek/library-service-api/internal/auth"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var hmacSecret = []byte("library-secret")
+
+func newTestKeySet(t *testing.T) (*auth.KeySet, *rsa.PrivateKey) {
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kid": "hmac-key", "kty": "oct", "k": base64.RawURLEncoding.EncodeToString(hmacSecret)},
+			{
+				"kid": "rsa-key", "kty": "RSA",
+				"n": base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			},
+		},
+	})
+	keySet, err := auth.ParseJWKS(jwks)
+	if err != nil {
+		t.Fatalf("Failed to parse jwks: %v", err)
+	}
+	return keySet, privateKey
+}
+
+func signToken(header, claims map[string]interface{}, sign func(signingInput string) []byte) string {
+	headerJSON, _ := json.Marshal(header)
+	claimsJSON, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign(signingInput))
+}
+
+func signHS256(signingInput string) []byte {
+	mac := hmac.New(sha256.New, hmacSecret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "librarian@library.test",
+		"iss":   "library-idp",
+		"aud":   []string{"library-service-api"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"librarian"},
+	}
+}
+
+func TestVerify_GivenValidHS256Token_ThenReturnPrincipal(t *testing.T) {
+	keySet, _ := newTestKeySet(t)
+	verifier := &auth.JWTVerifier{Keys: keySet, Issuer: "library-idp", Audience: "library-service-api"}
+
+	token := signToken(map[string]interface{}{"alg": "HS256", "kid": "hmac-key"}, validClaims(), signHS256)
+	principal, err := verifier.Verify(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "librarian@library.test", Roles: []string{"librarian"}, Method: "jwt"}, principal)
+}
+
+func TestVerify_GivenValidRS256Token_ThenReturnPrincipal(t *testing.T) {
+	keySet, privateKey := newTestKeySet(t)
+	verifier := &auth.JWTVerifier{Keys: keySet}
+
+	token := signToken(map[string]interface{}{"alg": "RS256", "kid": "rsa-key"}, validClaims(), func(signingInput string) []byte {
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, _ := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+		return signature
+	})
+	principal, err := verifier.Verify(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "librarian@library.test", principal.Subject)
+}
+
+func TestVerify_GivenExpiredToken_ThenReturnInvalidTokenError(t *testing.T) {
+	keySet, _ := newTestKeySet(t)
+	verifier := &auth.JWTVerifier{Keys: keySet}
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	token := signToken(map[string]interface{}{"alg": "HS256", "kid": "hmac-key"}, claims, signHS256)
+	_, err := verifier.Verify(token)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestVerify_GivenTamperedOrUnsignedToken_ThenReturnInvalidTokenError(t *testing.T) {
+	keySet, _ := newTestKeySet(t)
+	verifier := &auth.JWTVerifier{Keys: keySet}
+
+	wrongSecretToken := signToken(map[string]interface{}{"alg": "HS256", "kid": "hmac-key"}, validClaims(), func(signingInput string) []byte {
+		mac := hmac.New(sha256.New, []byte("wrong-secret"))
+		mac.Write([]byte(signingInput))
+		return mac.Sum(nil)
+	})
+	unsignedToken := signToken(map[string]interface{}{"alg": "none", "kid": "hmac-key"}, validClaims(), func(string) []byte { return nil })
+	confusedToken := signToken(map[string]interface{}{"alg": "HS256", "kid": "rsa-key"}, validClaims(), signHS256)
+
+	for _, token := range []string{wrongSecretToken, unsignedToken, confusedToken, "not-a-jwt"} {
+		_, err := verifier.Verify(token)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	}
+}
+
+func TestVerify_GivenWrongAudience_ThenReturnInvalidTokenError(t *testing.T) {
+	keySet, _ := newTestKeySet(t)
+	verifier := &auth.JWTVerifier{Keys: keySet, Audience: "another-service"}
+
+	token := signToken(map[string]interface{}{"alg": "HS256", "kid": "hmac-key"}, validClaims(), signHS256)
+	_, err := verifier.Verify(token)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
@@ -0,0 +1,56 @@
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	PermissionReadBooks      = "books:read"
+	PermissionWriteBooks     = "books:write"
+	PermissionDeleteBooks    = "books:delete"
+	PermissionImportBooks    = "books:import"
+	PermissionManageOwnLoans = "loans:manage:own"
+	PermissionManageWebhooks = "webhooks:manage"
+
+	// AnonymousRole is granted to callers that reached a route without
+	// credentials, which authentication only allows for public reads.
+	AnonymousRole = "anonymous"
+)
+
+type Policy struct {
+	Roles map[string][]string `json:"roles"`
+}
+
+func DefaultPolicy() *Policy {
+	return &Policy{Roles: map[string][]string{
+		AnonymousRole: {PermissionReadBooks},
+		"member":      {PermissionReadBooks, PermissionManageOwnLoans},
+		"librarian":   {PermissionReadBooks, PermissionWriteBooks, PermissionManageOwnLoans},
+		"admin":       {PermissionReadBooks, PermissionWriteBooks, PermissionDeleteBooks, PermissionImportBooks, PermissionManageOwnLoans, PermissionManageWebhooks},
+	}}
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy: %w", err)
+	}
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (policy *Policy) Allows(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, granted := range policy.Roles[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package authz_test
+
+import (
+	"gojek/library-service-api/internal/authz"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllows_GivenDefaultPolicy_ThenOnlyAdminsDeleteBooks(t *testing.T) {
+	policy := authz.DefaultPolicy()
+
+	assert.True(t, policy.Allows([]string{"admin"}, authz.PermissionDeleteBooks))
+	assert.False(t, policy.Allows([]string{"librarian"}, authz.PermissionDeleteBooks))
+	assert.False(t, policy.Allows([]string{"member"}, authz.PermissionDeleteBooks))
+}
+
+func TestAllows_GivenDefaultPolicy_ThenMembersOnlyReadBooksAndManageOwnLoans(t *testing.T) {
+	policy := authz.DefaultPolicy()
+
+	assert.True(t, policy.Allows([]string{"member"}, authz.PermissionReadBooks))
+	assert.True(t, policy.Allows([]string{"member"}, authz.PermissionManageOwnLoans))
+	assert.False(t, policy.Allows([]string{"member"}, authz.PermissionWriteBooks))
+}
+
+func TestAllows_GivenUnknownRole_ThenDeny(t *testing.T) {
+	assert.False(t, authz.DefaultPolicy().Allows([]string{"visitor"}, authz.PermissionReadBooks))
+}
+
+func TestLoadPolicy_GivenPolicyFile_ThenUseDeclaredRoles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	os.WriteFile(path, []byte(`{"roles":{"auditor":["books:read"]}}`), 0o600)
+
+	policy, err := authz.LoadPolicy(path)
+	assert.NoError(t, err)
+	assert.True(t, policy.Allows([]string{"auditor"}, authz.PermissionReadBooks))
+	assert.False(t, policy.Allows([]string{"admin"}, authz.PermissionReadBooks))
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores encoded values by key. Implementations expire entries on their
+// own, so a value may be missing at any time and callers must be able to load
+// it again.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// PubSub carries messages over the channels of a message broker. Subscribe
+// blocks, calling handle for each message published on channel, until ctx is
+// done or the subscription breaks.
+type PubSub interface {
+	Publish(ctx context.Context, channel, message string) error
+	Subscribe(ctx context.Context, channel string, handle func(message string)) error
+}
+
+// MemoryCache keeps entries in this process only, so replicas sharing a
+// database must evict the entries the others write.
+type MemoryCache struct {
+	lru *LRU[string, []byte]
+}
+
+func NewMemoryCache(capacity int, ttl time.Duration) *MemoryCache {
+	return &MemoryCache{lru: NewLRU[string, []byte](capacity, ttl)}
+}
+
+func (memoryCache *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, found := memoryCache.lru.Get(key)
+	return value, found, nil
+}
+
+func (memoryCache *MemoryCache) Set(ctx context.Context, key string, value []byte) error {
+	memoryCache.lru.Add(key, value)
+	return nil
+}
+
+func (memoryCache *MemoryCache) Delete(ctx context.Context, key string) error {
+	memoryCache.lru.Remove(key)
+	return nil
+}
+
+func (memoryCache *MemoryCache) Clear(ctx context.Context) error {
+	memoryCache.lru.Purge()
+	return nil
+}
+
+func (memoryCache *MemoryCache) Stats() Stats {
+	return memoryCache.lru.Stats()
+}
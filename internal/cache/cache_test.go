@@ -0,0 +1,38 @@
+package cache_test
+
+import (
+	"context"
+	"gojek/library-service-api/internal/cache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache_GivenStoredValue_ThenReturnUntilDeleted(t *testing.T) {
+	ctx := context.Background()
+	memoryCache := cache.NewMemoryCache(10, 0)
+	memoryCache.Set(ctx, "book:1", []byte("Clean Code"))
+
+	value, found, err := memoryCache.Get(ctx, "book:1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("Clean Code"), value)
+
+	memoryCache.Delete(ctx, "book:1")
+	_, found, _ = memoryCache.Get(ctx, "book:1")
+	assert.False(t, found)
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, memoryCache.Stats())
+}
+
+func TestMemoryCache_GivenClear_ThenDropEveryEntry(t *testing.T) {
+	ctx := context.Background()
+	memoryCache := cache.NewMemoryCache(10, 0)
+	memoryCache.Set(ctx, "book:1", []byte("Clean Code"))
+	memoryCache.Set(ctx, "book:2", []byte("Refactoring"))
+
+	assert.NoError(t, memoryCache.Clear(ctx))
+
+	_, found, _ := memoryCache.Get(ctx, "book:1")
+	assert.False(t, found)
+	assert.Equal(t, 0, memoryCache.Stats().Entries)
+}
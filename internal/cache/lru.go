@@ -0,0 +1,146 @@
+// Package cache holds caches for read-heavy lookups, kept in process or in a
+// Redis-protocol server shared by all replicas.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// LRU is a fixed-size cache that evicts the least recently used entry when
+// full. Entries older than the TTL are treated as missing; a zero TTL keeps
+// them until they are evicted or removed.
+type LRU[K comparable, V any] struct {
+	mu            sync.Mutex
+	capacity      int
+	ttl           time.Duration
+	entries       map[K]*list.Element
+	order         *list.List
+	invalidations uint64
+	stats         Stats
+}
+
+type lruEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	storedAt time.Time
+}
+
+func NewLRU[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{capacity: capacity, ttl: ttl, entries: map[K]*list.Element{}, order: list.New()}
+}
+
+func (lru *LRU[K, V]) Get(key K) (V, bool) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	return lru.get(key)
+}
+
+func (lru *LRU[K, V]) Add(key K, value V) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	lru.add(key, value)
+}
+
+// Remove drops key and keeps values loaded before the removal from being
+// stored by Load afterwards.
+func (lru *LRU[K, V]) Remove(key K) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	lru.invalidations++
+	if element, exists := lru.entries[key]; exists {
+		lru.removeElement(element)
+	}
+}
+
+// Purge drops every entry, and like Remove keeps values loaded before it from
+// being stored.
+func (lru *LRU[K, V]) Purge() {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	lru.invalidations++
+	lru.entries = map[K]*list.Element{}
+	lru.order.Init()
+}
+
+// Load returns the cached value for key, or calls load and caches what it
+// returns. load runs without the lock held, so a value it read is only stored
+// when no Remove happened meanwhile; otherwise it may already be stale.
+// Errors are returned as they are and not cached.
+func (lru *LRU[K, V]) Load(key K, load func() (V, error)) (V, error) {
+	lru.mu.Lock()
+	if value, found := lru.get(key); found {
+		lru.mu.Unlock()
+		return value, nil
+	}
+	invalidations := lru.invalidations
+	lru.mu.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if lru.invalidations == invalidations {
+		lru.add(key, value)
+	}
+	return value, nil
+}
+
+func (lru *LRU[K, V]) Stats() Stats {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	stats := lru.stats
+	stats.Entries = lru.order.Len()
+	return stats
+}
+
+func (lru *LRU[K, V]) get(key K) (V, bool) {
+	element, exists := lru.entries[key]
+	if !exists {
+		lru.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	entry := element.Value.(*lruEntry[K, V])
+	if lru.ttl > 0 && time.Since(entry.storedAt) > lru.ttl {
+		lru.removeElement(element)
+		lru.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	lru.order.MoveToFront(element)
+	lru.stats.Hits++
+	return entry.value, true
+}
+
+func (lru *LRU[K, V]) add(key K, value V) {
+	if lru.capacity <= 0 {
+		return
+	}
+	if element, exists := lru.entries[key]; exists {
+		element.Value = &lruEntry[K, V]{key: key, value: value, storedAt: time.Now()}
+		lru.order.MoveToFront(element)
+		return
+	}
+	lru.entries[key] = lru.order.PushFront(&lruEntry[K, V]{key: key, value: value, storedAt: time.Now()})
+	if lru.order.Len() > lru.capacity {
+		lru.removeElement(lru.order.Back())
+		lru.stats.Evictions++
+	}
+}
+
+func (lru *LRU[K, V]) removeElement(element *list.Element) {
+	lru.order.Remove(element)
+	delete(lru.entries, element.Value.(*lruEntry[K, V]).key)
+}
@@ -0,0 +1,91 @@
+package cache_test
+
+import (
+	"errors"
+	"gojek/library-service-api/internal/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_GivenCapacityExceeded_ThenEvictLeastRecentlyUsed(t *testing.T) {
+	lru := cache.NewLRU[int, string](2, 0)
+	lru.Add(1, "Clean Code")
+	lru.Add(2, "Dune")
+	lru.Get(1)
+	lru.Add(3, "Emma")
+
+	_, found := lru.Get(2)
+	assert.False(t, found)
+	value, found := lru.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, "Clean Code", value)
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 1, Evictions: 1, Entries: 2}, lru.Stats())
+}
+
+func TestLRU_GivenExpiredEntry_ThenReportMiss(t *testing.T) {
+	lru := cache.NewLRU[int, string](2, time.Millisecond)
+	lru.Add(1, "Clean Code")
+	time.Sleep(5 * time.Millisecond)
+
+	_, found := lru.Get(1)
+	assert.False(t, found)
+	assert.Equal(t, 0, lru.Stats().Entries)
+}
+
+func TestLRU_GivenLoadedKey_ThenServeFromCache(t *testing.T) {
+	lru := cache.NewLRU[int, string](2, 0)
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "Clean Code", nil
+	}
+
+	first, _ := lru.Load(1, load)
+	second, _ := lru.Load(1, load)
+
+	assert.Equal(t, "Clean Code", first)
+	assert.Equal(t, "Clean Code", second)
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 1, Entries: 1}, lru.Stats())
+}
+
+func TestLRU_GivenLoadError_ThenDoNotCache(t *testing.T) {
+	lru := cache.NewLRU[int, string](2, 0)
+	_, err := lru.Load(1, func() (string, error) { return "", errors.New("not found") })
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, lru.Stats().Entries)
+}
+
+func TestLRU_GivenRemoveDuringLoad_ThenDoNotStoreLoadedValue(t *testing.T) {
+	lru := cache.NewLRU[int, string](2, 0)
+	value, _ := lru.Load(1, func() (string, error) {
+		lru.Remove(1)
+		return "Old Title", nil
+	})
+
+	_, found := lru.Get(1)
+	assert.Equal(t, "Old Title", value)
+	assert.False(t, found)
+}
+
+func TestLRU_GivenPurgeDuringLoad_ThenDoNotStoreLoadedValue(t *testing.T) {
+	lru := cache.NewLRU[int, string](2, 0)
+	lru.Add(2, "Refactoring")
+	lru.Load(1, func() (string, error) {
+		lru.Purge()
+		return "Old Title", nil
+	})
+
+	assert.Equal(t, 0, lru.Stats().Entries)
+}
+
+func TestLRU_GivenZeroCapacity_ThenCacheNothing(t *testing.T) {
+	lru := cache.NewLRU[int, string](0, 0)
+	lru.Add(1, "Clean Code")
+
+	_, found := lru.Get(1)
+	assert.False(t, found)
+}
@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	redisMaxIdleConns          = 8
+	redisSubscribePingInterval = 30 * time.Second
+)
+
+var ErrRedisProtocol = errors.New("malformed redis reply")
+
+// RedisError is an error reply sent by the server.
+type RedisError string
+
+func (redisError RedisError) Error() string {
+	return "redis: " + string(redisError)
+}
+
+// RedisCache talks the Redis protocol (RESP) to a server shared by all
+// replicas. Entries are written with the TTL as their expiry. It also
+// implements PubSub on the same server. Each command is bounded by IOTimeout
+// as well as by its context, so a hung server cannot stall callers whose
+// context has no deadline.
+type RedisCache struct {
+	Addr        string
+	Password    string
+	TTL         time.Duration
+	DialTimeout time.Duration
+	IOTimeout   time.Duration
+	idle        chan *redisConn
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+}
+
+func NewRedisCache(addr, password string, ttl time.Duration) *RedisCache {
+	return &RedisCache{Addr: addr, Password: password, TTL: ttl, DialTimeout: 5 * time.Second, IOTimeout: 5 * time.Second,
+		idle: make(chan *redisConn, redisMaxIdleConns)}
+}
+
+func (redisCache *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := redisCache.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		redisCache.misses.Add(1)
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: GET returned %T", ErrRedisProtocol, reply)
+	}
+	redisCache.hits.Add(1)
+	return value, true, nil
+}
+
+func (redisCache *RedisCache) Set(ctx context.Context, key string, value []byte) error {
+	args := []string{"SET", key, string(value)}
+	if redisCache.TTL > 0 {
+		// Redis rejects PX 0, so TTLs under a millisecond are rounded up.
+		args = append(args, "PX", strconv.FormatInt(max(redisCache.TTL.Milliseconds(), 1), 10))
+	}
+	_, err := redisCache.do(ctx, args...)
+	return err
+}
+
+func (redisCache *RedisCache) Delete(ctx context.Context, key string) error {
+	_, err := redisCache.do(ctx, "DEL", key)
+	return err
+}
+
+func (redisCache *RedisCache) Publish(ctx context.Context, channel, message string) error {
+	_, err := redisCache.do(ctx, "PUBLISH", channel, message)
+	return err
+}
+
+// Subscribe holds a connection of its own for the subscription and pings the
+// server periodically, so a connection that silently died is noticed.
+func (redisCache *RedisCache) Subscribe(ctx context.Context, channel string, handle func(message string)) error {
+	conn, err := redisCache.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := conn.writeCommand("SUBSCRIBE", channel); err != nil {
+		return subscribeError(ctx, err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		pings := time.NewTicker(redisSubscribePingInterval)
+		defer pings.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-pings.C:
+				if conn.writeCommand("PING") != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * redisSubscribePingInterval))
+		reply, err := conn.readReply()
+		if err != nil {
+			return subscribeError(ctx, err)
+		}
+		items, ok := reply.([]interface{})
+		if !ok || len(items) != 3 {
+			continue
+		}
+		kind, _ := items[0].([]byte)
+		message, _ := items[2].([]byte)
+		if string(kind) == "message" {
+			handle(string(message))
+		}
+	}
+}
+
+func (redisCache *RedisCache) Stats() Stats {
+	return Stats{Hits: redisCache.hits.Load(), Misses: redisCache.misses.Load()}
+}
+
+func subscribeError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("redis subscription: %w", err)
+}
+
+// do sends one command on a pooled connection, closing it if ctx is done
+// first. Connections that fail with anything but an error reply are closed,
+// since their stream may be out of step with the commands sent.
+func (redisCache *RedisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+	var conn *redisConn
+	select {
+	case conn = <-redisCache.idle:
+	default:
+		var err error
+		if conn, err = redisCache.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	conn.SetDeadline(redisCache.deadline(ctx))
+	reply, err := conn.roundTrip(args...)
+	if !stop() {
+		return nil, fmt.Errorf("redis %s: %w", args[0], ctx.Err())
+	}
+	var redisError RedisError
+	if err != nil && !errors.As(err, &redisError) {
+		conn.Close()
+		return nil, fmt.Errorf("redis %s: %w", args[0], err)
+	}
+
+	select {
+	case redisCache.idle <- conn:
+	default:
+		conn.Close()
+	}
+	return reply, err
+}
+
+func (redisCache *RedisCache) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: redisCache.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", redisCache.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis dial: %w", err)
+	}
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if redisCache.Password != "" {
+		conn.SetDeadline(redisCache.deadline(ctx))
+		if _, err := conn.roundTrip("AUTH", redisCache.Password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+		conn.SetDeadline(time.Time{})
+	}
+	return conn, nil
+}
+
+// deadline is the earlier of ctx's deadline and IOTimeout from now.
+func (redisCache *RedisCache) deadline(ctx context.Context) time.Time {
+	deadline, hasDeadline := ctx.Deadline()
+	if redisCache.IOTimeout > 0 {
+		if timeout := time.Now().Add(redisCache.IOTimeout); !hasDeadline || timeout.Before(deadline) {
+			deadline = timeout
+		}
+	}
+	return deadline
+}
+
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (conn *redisConn) roundTrip(args ...string) (interface{}, error) {
+	if err := conn.writeCommand(args...); err != nil {
+		return nil, err
+	}
+	return conn.readReply()
+}
+
+func (conn *redisConn) writeCommand(args ...string) error {
+	command := strings.Builder{}
+	command.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		command.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	_, err := io.WriteString(conn.Conn, command.String())
+	return err
+}
+
+// readReply returns simple strings as string, integers as int64, bulk strings
+// as []byte, arrays as []interface{} and nil bulk strings or arrays as nil.
+func (conn *redisConn) readReply() (interface{}, error) {
+	line, err := conn.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("%w: empty line", ErrRedisProtocol)
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		value, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrRedisProtocol, line)
+		}
+		return value, nil
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrRedisProtocol, line)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(conn.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:length], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrRedisProtocol, line)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = conn.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrRedisProtocol, line)
+}
@@ -0,0 +1,167 @@
+package cache_test
+
+import (
+	"context"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/cache/resptest"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisCache_GivenStoredValue_ThenReturnUntilDeleted(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	redisCache := cache.NewRedisCache(server.Addr, "", time.Minute)
+
+	assert.NoError(t, redisCache.Set(ctx, "book:1", []byte("Clean Code\r\n")))
+	value, found, err := redisCache.Get(ctx, "book:1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("Clean Code\r\n"), value)
+
+	assert.NoError(t, redisCache.Delete(ctx, "book:1"))
+	_, found, err = redisCache.Get(ctx, "book:1")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, redisCache.Stats())
+	assert.Equal(t, []string{"SET", "book:1", "Clean Code\r\n", "PX", "60000"}, server.Commands()[0])
+}
+
+func TestRedisCache_GivenExpiredValue_ThenReportMiss(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	redisCache := cache.NewRedisCache(server.Addr, "", time.Millisecond)
+
+	redisCache.Set(ctx, "book:1", []byte("Clean Code"))
+	time.Sleep(5 * time.Millisecond)
+
+	_, found, err := redisCache.Get(ctx, "book:1")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRedisCache_GivenPassword_ThenAuthenticateEachConnection(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	redisCache := cache.NewRedisCache(server.Addr, "secret", 0)
+
+	redisCache.Get(context.Background(), "book:1")
+
+	assert.Equal(t, [][]string{{"AUTH", "secret"}, {"GET", "book:1"}}, server.Commands())
+}
+
+func TestRedisCache_GivenDroppedConnection_ThenRedialOnNextCommand(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	redisCache := cache.NewRedisCache(server.Addr, "", 0)
+	redisCache.Set(ctx, "book:1", []byte("Clean Code"))
+
+	server.CloseClientConnections()
+	_, _, err := redisCache.Get(ctx, "book:1")
+	assert.Error(t, err)
+
+	value, found, err := redisCache.Get(ctx, "book:1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("Clean Code"), value)
+}
+
+func TestRedisCache_GivenUnreachableServer_ThenReturnError(t *testing.T) {
+	server := resptest.NewServer()
+	server.Close()
+	redisCache := cache.NewRedisCache(server.Addr, "", 0)
+
+	_, _, err := redisCache.Get(context.Background(), "book:1")
+	assert.Error(t, err)
+}
+
+func TestRedisCache_GivenSubscription_ThenDeliverPublishedMessages(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	redisCache := cache.NewRedisCache(server.Addr, "", 0)
+
+	messages := make(chan string, 1)
+	subscribed := make(chan error, 1)
+	go func() {
+		subscribed <- redisCache.Subscribe(ctx, "books", func(message string) { messages <- message })
+	}()
+	assert.Eventually(t, func() bool { return server.Subscribers("books") == 1 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, redisCache.Publish(ctx, "books", "1"))
+	select {
+	case message := <-messages:
+		assert.Equal(t, "1", message)
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-subscribed, context.Canceled)
+}
+
+func TestRedisCache_GivenSubMillisecondTTL_ThenExpireAfterOneMillisecond(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	redisCache := cache.NewRedisCache(server.Addr, "", 500*time.Microsecond)
+
+	assert.NoError(t, redisCache.Set(context.Background(), "book:1", []byte("Clean Code")))
+
+	assert.Equal(t, []string{"SET", "book:1", "Clean Code", "PX", "1"}, server.Commands()[0])
+}
+
+func TestRedisCache_GivenUnresponsiveServer_ThenTimeOutWithoutContextDeadline(t *testing.T) {
+	listener := newSilentListener(t)
+	defer listener.Close()
+	redisCache := cache.NewRedisCache(listener.Addr().String(), "", 0)
+	redisCache.IOTimeout = 20 * time.Millisecond
+
+	_, _, err := redisCache.Get(context.Background(), "book:1")
+
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+}
+
+func TestRedisCache_GivenContextCanceledDuringCommand_ThenReturnCanceled(t *testing.T) {
+	listener := newSilentListener(t)
+	defer listener.Close()
+	redisCache := cache.NewRedisCache(listener.Addr().String(), "", 0)
+	redisCache.IOTimeout = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, _, err := redisCache.Get(ctx, "book:1")
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// newSilentListener accepts connections and never answers on them, as a hung
+// server would.
+func newSilentListener(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go func() {
+		conns := []net.Conn{}
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	return listener
+}
@@ -0,0 +1,245 @@
+// Package resptest runs an in-process server that speaks enough of the Redis
+// protocol (RESP) to test cache clients without a real Redis.
+package resptest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server supports PING, AUTH, GET, SET with PX, DEL, PUBLISH and SUBSCRIBE.
+// Commands lists every command received, in order, for tests to inspect.
+type Server struct {
+	Addr     string
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu          sync.Mutex
+	values      map[string]entry
+	subscribers map[string][]*client
+	clients     map[*client]struct{}
+	commands    [][]string
+}
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+type client struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+}
+
+// NewServer starts a server on a loopback port and panics if it cannot
+// listen, as httptest.NewServer does.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("resptest: failed to listen: %v", err))
+	}
+	server := &Server{Addr: listener.Addr().String(), listener: listener,
+		values: map[string]entry{}, subscribers: map[string][]*client{}, clients: map[*client]struct{}{}}
+	server.wg.Add(1)
+	go server.accept()
+	return server
+}
+
+// Close stops accepting connections, drops the open ones and waits for their
+// handlers to return.
+func (server *Server) Close() {
+	server.listener.Close()
+	server.CloseClientConnections()
+	server.wg.Wait()
+}
+
+// CloseClientConnections drops every open connection, as a server restart or
+// network failure would.
+func (server *Server) CloseClientConnections() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for client := range server.clients {
+		client.conn.Close()
+	}
+}
+
+func (server *Server) Commands() [][]string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([][]string(nil), server.commands...)
+}
+
+// Subscribers returns how many connections are subscribed to channel.
+func (server *Server) Subscribers(channel string) int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return len(server.subscribers[channel])
+}
+
+func (server *Server) accept() {
+	defer server.wg.Done()
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		client := &client{conn: conn}
+		server.mu.Lock()
+		server.clients[client] = struct{}{}
+		server.mu.Unlock()
+		server.wg.Add(1)
+		go server.serve(client)
+	}
+}
+
+func (server *Server) serve(client *client) {
+	defer server.wg.Done()
+	defer server.disconnect(client)
+	reader := bufio.NewReader(client.conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		server.mu.Lock()
+		server.commands = append(server.commands, args)
+		server.mu.Unlock()
+		client.write(server.execute(client, args))
+	}
+}
+
+func (server *Server) disconnect(client *client) {
+	client.conn.Close()
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	delete(server.clients, client)
+	for channel, subscribers := range server.subscribers {
+		for i, subscriber := range subscribers {
+			if subscriber == client {
+				server.subscribers[channel] = append(subscribers[:i:i], subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (server *Server) execute(client *client, args []string) string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "AUTH":
+		return "+OK\r\n"
+	case "GET":
+		if len(args) != 2 {
+			return wrongArguments(args[0])
+		}
+		stored, exists := server.values[args[1]]
+		if !exists || (!stored.expiresAt.IsZero() && time.Now().After(stored.expiresAt)) {
+			delete(server.values, args[1])
+			return "$-1\r\n"
+		}
+		return bulkString(stored.value)
+	case "SET":
+		if len(args) != 3 && len(args) != 5 {
+			return wrongArguments(args[0])
+		}
+		stored := entry{value: args[2]}
+		if len(args) == 5 {
+			milliseconds, err := strconv.Atoi(args[4])
+			if !strings.EqualFold(args[3], "PX") || err != nil || milliseconds <= 0 {
+				return "-ERR syntax error\r\n"
+			}
+			stored.expiresAt = time.Now().Add(time.Duration(milliseconds) * time.Millisecond)
+		}
+		server.values[args[1]] = stored
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, exists := server.values[key]; exists {
+				delete(server.values, key)
+				deleted++
+			}
+		}
+		return ":" + strconv.Itoa(deleted) + "\r\n"
+	case "PUBLISH":
+		if len(args) != 3 {
+			return wrongArguments(args[0])
+		}
+		subscribers := server.subscribers[args[1]]
+		for _, subscriber := range subscribers {
+			subscriber.write("*3\r\n" + bulkString("message") + bulkString(args[1]) + bulkString(args[2]))
+		}
+		return ":" + strconv.Itoa(len(subscribers)) + "\r\n"
+	case "SUBSCRIBE":
+		reply := ""
+		for i, channel := range args[1:] {
+			server.subscribers[channel] = append(server.subscribers[channel], client)
+			reply += "*3\r\n" + bulkString("subscribe") + bulkString(channel) + ":" + strconv.Itoa(i+1) + "\r\n"
+		}
+		return reply
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func (client *client) write(reply string) {
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+	io.WriteString(client.conn, reply)
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	count, err := readLength(reader, '*')
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("resptest: empty command")
+	}
+	args := make([]string, count)
+	for i := range args {
+		length, err := readLength(reader, '$')
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:length])
+	}
+	return args, nil
+}
+
+func readLength(reader *bufio.Reader, prefix byte) (int, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) < 2 || line[0] != prefix {
+		return 0, fmt.Errorf("resptest: expected %q, got %q", prefix, line)
+	}
+	length, err := strconv.Atoi(line[1:])
+	if err != nil || length < 0 {
+		return 0, fmt.Errorf("resptest: bad length %q", line)
+	}
+	return length, nil
+}
+
+func bulkString(value string) string {
+	return "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+}
+
+func wrongArguments(command string) string {
+	return "-ERR wrong number of arguments for '" + strings.ToLower(command) + "' command\r\n"
+}
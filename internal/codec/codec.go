@@ -0,0 +1,203 @@
+// Package codec encodes and decodes API bodies in the media types the books
+// API offers, and picks one of them from the Accept or Content-Type header.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotAcceptable        = errors.New("no acceptable media type")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrUnsupportedValue     = errors.New("value cannot be represented in this media type")
+)
+
+type Codec interface {
+	// MediaTypes lists the media types the codec answers to, the canonical
+	// one first.
+	MediaTypes() []string
+	ContentType() string
+	Encode(w io.Writer, v interface{}) error
+	// Decode reads a single value from data. With disallowUnknownFields it
+	// also rejects fields v has no place for and data after the value.
+	Decode(data []byte, v interface{}, disallowUnknownFields bool) error
+}
+
+var (
+	JSON        Codec = jsonCodec{}
+	XML         Codec = xmlCodec{}
+	CSV         Codec = csvCodec{}
+	MessagePack Codec = messagePackCodec{}
+	NDJSON      Codec = ndjsonCodec{}
+)
+
+// Codecs is every supported codec in the order the server prefers them when
+// the client does not.
+var Codecs = []Codec{JSON, XML, CSV, MessagePack, NDJSON}
+
+// Negotiate picks the codec for an Accept header. A codec takes the quality of
+// the most specific media range that matches it; ties go to the more specific
+// range, then to the server's order. An empty header accepts JSON.
+func Negotiate(accept string) (Codec, error) {
+	if strings.TrimSpace(accept) == "" {
+		return JSON, nil
+	}
+	mediaRanges := parseAccept(accept)
+
+	var best Codec
+	bestQuality, bestSpecificity := 0.0, -1
+	for _, codec := range Codecs {
+		quality, specificity := codecQuality(codec, mediaRanges)
+		if quality > bestQuality || (quality == bestQuality && quality > 0 && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = codec, quality, specificity
+		}
+	}
+	if best == nil {
+		return nil, ErrNotAcceptable
+	}
+	return best, nil
+}
+
+// ForContentType picks the codec for a request body. Bodies without a
+// Content-Type are read as JSON, as they were before other types existed.
+func ForContentType(contentType string) (Codec, error) {
+	if strings.TrimSpace(contentType) == "" {
+		return JSON, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+	for _, codec := range Codecs {
+		for _, supported := range codec.MediaTypes() {
+			if mediaType == supported {
+				return codec, nil
+			}
+		}
+	}
+	return nil, ErrUnsupportedMediaType
+}
+
+type mediaRange struct {
+	mainType, subType string
+	quality           float64
+}
+
+func (mediaRange mediaRange) specificity() int {
+	switch {
+	case mediaRange.mainType == "*":
+		return 0
+	case mediaRange.subType == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (mediaRange mediaRange) matches(mediaType string) bool {
+	mainType, subType, _ := strings.Cut(mediaType, "/")
+	return (mediaRange.mainType == "*" || mediaRange.mainType == mainType) &&
+		(mediaRange.subType == "*" || mediaRange.subType == subType)
+}
+
+func parseAccept(accept string) []mediaRange {
+	mediaRanges := []mediaRange{}
+	for _, element := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(element))
+		if err != nil {
+			continue
+		}
+		mainType, subType, found := strings.Cut(mediaType, "/")
+		if !found {
+			continue
+		}
+		quality := 1.0
+		if value, exists := params["q"]; exists {
+			if quality, err = strconv.ParseFloat(value, 64); err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		mediaRanges = append(mediaRanges, mediaRange{mainType: mainType, subType: subType, quality: quality})
+	}
+	sort.SliceStable(mediaRanges, func(i, j int) bool {
+		return mediaRanges[i].specificity() > mediaRanges[j].specificity()
+	})
+	return mediaRanges
+}
+
+// codecQuality expects mediaRanges sorted most specific first, so that an
+// explicit "q=0" refuses a codec that a wildcard would otherwise accept.
+// Wildcards match the canonical media type only, so "text/*" means CSV rather
+// than the text/xml alias.
+func codecQuality(codec Codec, mediaRanges []mediaRange) (float64, int) {
+	for _, mediaRange := range mediaRanges {
+		mediaTypes := codec.MediaTypes()
+		if mediaRange.specificity() < 2 {
+			mediaTypes = mediaTypes[:1]
+		}
+		for _, mediaType := range mediaTypes {
+			if mediaRange.matches(mediaType) {
+				return mediaRange.quality, mediaRange.specificity()
+			}
+		}
+	}
+	return 0, -1
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) MediaTypes() []string { return []string{"application/json"} }
+
+func (jsonCodec) ContentType() string { return "application/json" }
+
+func (jsonCodec) Encode(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (jsonCodec) Decode(data []byte, v interface{}, disallowUnknownFields bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if !disallowUnknownFields {
+		return decoder.Decode(v)
+	}
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the JSON body")
+	}
+	return nil
+}
+
+// xmlCodec cannot enforce disallowUnknownFields: encoding/xml skips elements
+// it has no field for. A wrong root element is still rejected for types that
+// declare an XMLName.
+type xmlCodec struct{}
+
+func (xmlCodec) MediaTypes() []string { return []string{"application/xml", "text/xml"} }
+
+func (xmlCodec) ContentType() string { return "application/xml; charset=utf-8" }
+
+func (xmlCodec) Encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func (xmlCodec) Decode(data []byte, v interface{}, _ bool) error {
+	return xml.Unmarshal(data, v)
+}
@@ -0,0 +1,85 @@
+package codec_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"gojek/library-service-api/internal/codec"
+	v1 "gojek/library-service-api/internal/dto/v1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate_GivenAcceptHeaders_ThenPickMatchingCodec(t *testing.T) {
+	cases := map[string]codec.Codec{
+		"":                                     codec.JSON,
+		"*/*":                                  codec.JSON,
+		"application/xml":                      codec.XML,
+		"text/xml":                             codec.XML,
+		"text/*":                               codec.CSV,
+		"application/x-msgpack":                codec.MessagePack,
+		"application/xml, */*":                 codec.XML,
+		"application/json;q=0.5, text/csv":     codec.CSV,
+		"*/*, application/json;q=0":            codec.XML,
+		"image/png, application/msgpack;q=0.1": codec.MessagePack,
+	}
+	for accept, expected := range cases {
+		negotiated, err := codec.Negotiate(accept)
+		assert.NoError(t, err, accept)
+		assert.Equal(t, expected, negotiated, accept)
+	}
+}
+
+func TestNegotiate_GivenNoSupportedType_ThenReturnErrNotAcceptable(t *testing.T) {
+	for _, accept := range []string{"image/png", "application/json;q=0", "text/html, application/pdf"} {
+		_, err := codec.Negotiate(accept)
+		assert.ErrorIs(t, err, codec.ErrNotAcceptable, accept)
+	}
+}
+
+func TestForContentType_GivenContentTypes_ThenPickCodecOrReturnErrUnsupportedMediaType(t *testing.T) {
+	requestCodec, err := codec.ForContentType("")
+	assert.NoError(t, err)
+	assert.Equal(t, codec.JSON, requestCodec)
+
+	requestCodec, err = codec.ForContentType("application/xml; charset=utf-8")
+	assert.NoError(t, err)
+	assert.Equal(t, codec.XML, requestCodec)
+
+	_, err = codec.ForContentType("application/x-www-form-urlencoded")
+	assert.ErrorIs(t, err, codec.ErrUnsupportedMediaType)
+}
+
+func TestCodecs_GivenBookList_ThenRoundTripInEveryMediaType(t *testing.T) {
+	bookList := v1.BookList{Books: []v1.Book{
+		{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"},
+		{ID: 2, Title: "Dune, Part One", Price: 9.5, PublishedDate: "1965-08-01"},
+	}}
+	for _, supported := range []codec.Codec{codec.JSON, codec.XML, codec.MessagePack} {
+		data := bytes.Buffer{}
+		assert.NoError(t, supported.Encode(&data, bookList))
+
+		decoded := v1.BookList{}
+		assert.NoError(t, supported.Decode(data.Bytes(), &decoded, true), supported.ContentType())
+		expected, _ := json.Marshal(bookList)
+		actual, _ := json.Marshal(decoded)
+		assert.JSONEq(t, string(expected), string(actual), supported.ContentType())
+	}
+}
+
+func TestXML_GivenBook_ThenEncodeNamedElements(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.XML.Encode(&data, v1.Book{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<book><id>1</id><title>Clean Code</title><price>10.99</price><publishedDate>2008-08-01</publishedDate></book>`,
+		data.String())
+}
+
+func TestJSON_GivenUnknownFieldOrTrailingData_ThenRejectOnlyWhenStrict(t *testing.T) {
+	request := v1.UpdateBookTitleRequest{}
+	assert.NoError(t, codec.JSON.Decode([]byte(`{"title":"Dune","author":"Herbert"}`), &request, false))
+	assert.Error(t, codec.JSON.Decode([]byte(`{"title":"Dune","author":"Herbert"}`), &request, true))
+	assert.Error(t, codec.JSON.Decode([]byte(`{"title":"Dune"} {}`), &request, true))
+}
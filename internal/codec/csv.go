@@ -0,0 +1,191 @@
+package codec
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// csvCodec writes a struct of scalar fields as a header and one record, and a
+// list envelope, a struct whose only field is a slice of such structs, as a
+// header and one record per element. Columns are named after the json tags.
+type csvCodec struct{}
+
+func (csvCodec) MediaTypes() []string { return []string{"text/csv"} }
+
+func (csvCodec) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (csvCodec) Encode(w io.Writer, v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return ErrUnsupportedValue
+	}
+
+	rows := value
+	fields := csvFields(value.Type())
+	if elements, isList := listEnvelope(value); isList {
+		if elements.Type().Elem().Kind() != reflect.Struct {
+			return ErrUnsupportedValue
+		}
+		rows, fields = elements, csvFields(elements.Type().Elem())
+	}
+
+	header := make([]string, 0, len(fields))
+	for _, field := range fields {
+		header = append(header, field.Name)
+	}
+	records := [][]string{header}
+	appendRecord := func(row reflect.Value) error {
+		record := make([]string, 0, len(fields))
+		for _, field := range fields {
+			cell, err := formatCSVValue(row.FieldByIndex(field.Index))
+			if err != nil {
+				return err
+			}
+			record = append(record, cell)
+		}
+		records = append(records, record)
+		return nil
+	}
+
+	if rows.Kind() == reflect.Slice {
+		for i := 0; i < rows.Len(); i++ {
+			if err := appendRecord(rows.Index(i)); err != nil {
+				return err
+			}
+		}
+	} else if err := appendRecord(rows); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	return writer.WriteAll(records)
+}
+
+// Decode reads a header and exactly one record into a struct of scalar fields.
+func (csvCodec) Decode(data []byte, v interface{}, disallowUnknownFields bool) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return ErrUnsupportedValue
+	}
+	value = value.Elem()
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) != 2 {
+		return errors.New("expected a header and exactly one record")
+	}
+
+	fieldsByName := map[string]csvField{}
+	for _, field := range csvFields(value.Type()) {
+		fieldsByName[field.Name] = field
+	}
+	for i, column := range records[0] {
+		field, exists := fieldsByName[column]
+		if !exists {
+			if disallowUnknownFields {
+				return fmt.Errorf("unknown column %q", column)
+			}
+			continue
+		}
+		if err := parseCSVValue(value.FieldByIndex(field.Index), records[1][i]); err != nil {
+			return fmt.Errorf("column %q: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// listEnvelope returns the elements of a struct whose only field is a slice,
+// such as a book list.
+func listEnvelope(value reflect.Value) (reflect.Value, bool) {
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	fields := csvFields(value.Type())
+	if len(fields) != 1 || fields[0].Type.Kind() != reflect.Slice {
+		return reflect.Value{}, false
+	}
+	return value.FieldByIndex(fields[0].Index), true
+}
+
+type csvField struct {
+	Name  string
+	Index []int
+	Type  reflect.Type
+}
+
+func csvFields(structType reflect.Type) []csvField {
+	fields := []csvField{}
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, csvField{Name: name, Index: field.Index, Type: field.Type})
+	}
+	return fields
+}
+
+func formatCSVValue(value reflect.Value) (string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	default:
+		return "", ErrUnsupportedValue
+	}
+}
+
+func parseCSVValue(value reflect.Value, cell string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(cell)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(cell, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(cell, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(cell, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	default:
+		return ErrUnsupportedValue
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package codec_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/codec"
+	"gojek/library-service-api/internal/dto"
+	v1 "gojek/library-service-api/internal/dto/v1"
+	v2 "gojek/library-service-api/internal/dto/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSV_GivenBookList_ThenWriteHeaderAndOneRecordPerBook(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.CSV.Encode(&data, v2.BookList{Books: []v2.Book{
+		{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"},
+		{ID: 2, Title: "Dune, Part One", Price: 9.5, PublishedDate: "1965-08-01"},
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,title,price,publishedDate\r\n"+
+		"1,Clean Code,10.99,2008-08-01\r\n"+
+		"2,\"Dune, Part One\",9.5,1965-08-01\r\n", data.String())
+}
+
+func TestCSV_GivenEmptyBookList_ThenWriteHeaderOnly(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.CSV.Encode(&data, v1.NewBookList(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,title,price,publishedDate\r\n", data.String())
+}
+
+func TestCSV_GivenBook_ThenWriteHeaderAndOneRecord(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.CSV.Encode(&data, v1.Book{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,title,price,publishedDate\r\n1,Clean Code,10.99,2008-08-01\r\n", data.String())
+}
+
+func TestCSV_GivenNonStructValue_ThenReturnErrUnsupportedValue(t *testing.T) {
+	err := codec.CSV.Encode(&bytes.Buffer{}, map[string]interface{}{"data": nil})
+
+	assert.ErrorIs(t, err, codec.ErrUnsupportedValue)
+	assert.NoError(t, codec.CSV.Encode(&bytes.Buffer{}, dto.Error{Error: "Book not found."}))
+}
+
+func TestCSV_GivenHeaderAndRecord_ThenDecodeRequest(t *testing.T) {
+	request := v2.CreateBookRequest{}
+	err := codec.CSV.Decode([]byte("publishedDate,title,price\n1965-08-01,Dune,9.99\n"), &request, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, v2.CreateBookRequest{Title: "Dune", Price: 9.99, PublishedDate: "1965-08-01"}, request)
+}
+
+func TestCSV_GivenUnknownColumn_ThenRejectOnlyWhenStrict(t *testing.T) {
+	data := []byte("title,author\nDune,Herbert\n")
+
+	assert.NoError(t, codec.CSV.Decode(data, &v1.UpdateBookTitleRequest{}, false))
+	assert.Error(t, codec.CSV.Decode(data, &v1.UpdateBookTitleRequest{}, true))
+}
+
+func TestCSV_GivenMoreThanOneRecordOrMalformedValue_ThenReturnError(t *testing.T) {
+	assert.Error(t, codec.CSV.Decode([]byte("title\nDune\nEmma\n"), &v2.UpdateBookRequest{}, true))
+	assert.Error(t, codec.CSV.Decode([]byte("title,price\nDune,cheap\n"), &v2.CreateBookRequest{}, true))
+}
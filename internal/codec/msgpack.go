@@ -0,0 +1,39 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// messagePackCodec names map keys after the json tags, so a body has the same
+// field names in every media type.
+type messagePackCodec struct{}
+
+func (messagePackCodec) MediaTypes() []string {
+	return []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+}
+
+func (messagePackCodec) ContentType() string { return "application/msgpack" }
+
+func (messagePackCodec) Encode(w io.Writer, v interface{}) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	return encoder.Encode(v)
+}
+
+func (messagePackCodec) Decode(data []byte, v interface{}, disallowUnknownFields bool) error {
+	reader := bytes.NewReader(data)
+	decoder := msgpack.NewDecoder(reader)
+	decoder.SetCustomStructTag("json")
+	decoder.DisallowUnknownFields(disallowUnknownFields)
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if disallowUnknownFields && reader.Len() > 0 {
+		return errors.New("unexpected data after the MessagePack body")
+	}
+	return nil
+}
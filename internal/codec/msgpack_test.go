@@ -0,0 +1,32 @@
+package codec_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/codec"
+	v2 "gojek/library-service-api/internal/dto/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMessagePack_GivenBook_ThenUseJSONFieldNames(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.MessagePack.Encode(&data, v2.Book{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"})
+	assert.NoError(t, err)
+
+	decoded := map[string]interface{}{}
+	assert.NoError(t, msgpack.Unmarshal(data.Bytes(), &decoded))
+	assert.Equal(t, "Clean Code", decoded["title"])
+	assert.Equal(t, "2008-08-01", decoded["publishedDate"])
+	assert.NotContains(t, decoded, "XMLName")
+}
+
+func TestMessagePack_GivenUnknownFieldOrTrailingData_ThenRejectOnlyWhenStrict(t *testing.T) {
+	withUnknownField, _ := msgpack.Marshal(map[string]interface{}{"title": "Dune", "author": "Herbert"})
+	assert.NoError(t, codec.MessagePack.Decode(withUnknownField, &v2.UpdateBookRequest{}, false))
+	assert.Error(t, codec.MessagePack.Decode(withUnknownField, &v2.UpdateBookRequest{}, true))
+
+	valid, _ := msgpack.Marshal(map[string]interface{}{"title": "Dune"})
+	assert.Error(t, codec.MessagePack.Decode(append(valid, valid...), &v2.UpdateBookRequest{}, true))
+}
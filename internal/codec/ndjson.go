@@ -0,0 +1,33 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// ndjsonCodec writes each element of a list envelope as one JSON line, and any
+// other value as a single line. A request body is read as one JSON value.
+type ndjsonCodec struct{}
+
+func (ndjsonCodec) MediaTypes() []string { return []string{"application/x-ndjson"} }
+
+func (ndjsonCodec) ContentType() string { return "application/x-ndjson" }
+
+func (ndjsonCodec) Encode(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	elements, isList := listEnvelope(reflect.Indirect(reflect.ValueOf(v)))
+	if !isList {
+		return encoder.Encode(v)
+	}
+	for i := 0; i < elements.Len(); i++ {
+		if err := encoder.Encode(elements.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ndjsonCodec) Decode(data []byte, v interface{}, disallowUnknownFields bool) error {
+	return JSON.Decode(data, v, disallowUnknownFields)
+}
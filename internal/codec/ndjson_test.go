@@ -0,0 +1,30 @@
+package codec_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/codec"
+	v2 "gojek/library-service-api/internal/dto/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNDJSON_GivenBookList_ThenWriteOneLinePerBook(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.NDJSON.Encode(&data, v2.BookList{Books: []v2.Book{
+		{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"},
+		{ID: 2, Title: "Dune", Price: 9.5, PublishedDate: "1965-08-01"},
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"title":"Clean Code","price":10.99,"publishedDate":"2008-08-01"}`+"\n"+
+		`{"id":2,"title":"Dune","price":9.5,"publishedDate":"1965-08-01"}`+"\n", data.String())
+}
+
+func TestNDJSON_GivenBook_ThenWriteSingleLine(t *testing.T) {
+	data := bytes.Buffer{}
+	err := codec.NDJSON.Encode(&data, v2.Book{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "2008-08-01"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"title":"Clean Code","price":10.99,"publishedDate":"2008-08-01"}`+"\n", data.String())
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+type APIVersionConfig struct {
+	V1DeprecatedAt time.Time
+	V1SunsetAt     time.Time
+}
+
+func NewAPIVersionConfig() APIVersionConfig {
+	return APIVersionConfig{
+		V1DeprecatedAt: GetEnvTime("API_V1_DEPRECATED_AT", time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)),
+		V1SunsetAt:     GetEnvTime("API_V1_SUNSET_AT", time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)),
+	}
+}
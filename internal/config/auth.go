@@ -0,0 +1,19 @@
+package config
+
+type AuthConfig struct {
+	JWKSFile    string
+	JWTIssuer   string
+	JWTAudience string
+	PublicReads bool
+	PolicyFile  string
+}
+
+func NewAuthConfig() AuthConfig {
+	return AuthConfig{
+		JWKSFile:    GetEnv("AUTH_JWKS_FILE", ""),
+		JWTIssuer:   GetEnv("AUTH_JWT_ISSUER", ""),
+		JWTAudience: GetEnv("AUTH_JWT_AUDIENCE", ""),
+		PublicReads: GetEnvBool("AUTH_PUBLIC_READS", true),
+		PolicyFile:  GetEnv("AUTHZ_POLICY_FILE", ""),
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// CacheConfig selects where looked-up books are cached. The memory backend
+// keeps a cache per replica, which the book changes announced by the database
+// keep current; the redis backend shares one cache between all replicas.
+type CacheConfig struct {
+	BookCacheBackend string
+	BookCacheSize    int
+	BookCacheTTL     time.Duration
+	RedisAddr        string
+	RedisPassword    string
+	HTTPMaxAge       time.Duration
+}
+
+func NewCacheConfig() CacheConfig {
+	return CacheConfig{
+		BookCacheBackend: GetEnv("BOOK_CACHE_BACKEND", "memory"),
+		BookCacheSize:    GetEnvInt("BOOK_CACHE_SIZE", 1000),
+		BookCacheTTL:     GetEnvDuration("BOOK_CACHE_TTL", 5*time.Minute),
+		RedisAddr:        GetEnv("REDIS_ADDR", ""),
+		RedisPassword:    GetEnv("REDIS_PASSWORD", ""),
+		HTTPMaxAge:       GetEnvDuration("HTTP_CACHE_MAX_AGE", time.Minute),
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func NewCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   GetEnvList("CORS_ALLOWED_ORIGINS", nil),
+		AllowedMethods:   GetEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE"}),
+		AllowedHeaders:   GetEnvList("CORS_ALLOWED_HEADERS", []string{"Authorization", "Content-Type", "X-API-Key", "X-Request-ID", "Idempotency-Key"}),
+		ExposedHeaders:   GetEnvList("CORS_EXPOSED_HEADERS", []string{"X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Idempotent-Replayed", "Deprecation", "Sunset", "Link", "Last-Modified", "traceparent"}),
+		AllowCredentials: GetEnvBool("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           GetEnvDuration("CORS_MAX_AGE", 10*time.Minute),
+	}
+}
+
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            time.Duration
+	ContentSecurityPolicy string
+}
+
+func NewSecurityHeadersConfig() SecurityHeadersConfig {
+	return SecurityHeadersConfig{
+		HSTSMaxAge:            GetEnvDuration("SECURITY_HSTS_MAX_AGE", 365*24*time.Hour),
+		ContentSecurityPolicy: GetEnv("SECURITY_CONTENT_SECURITY_POLICY", "default-src 'self'; frame-ancestors 'none'"),
+	}
+}
+
+// GetEnvList reads a comma-separated list, ignoring blank entries.
+func GetEnvList(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -1,22 +1,38 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 type DBConfig struct {
-	Host     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Host         string
+	User         string
+	Password     string
+	DBName       string
+	SSLMode      string
+	QueryTimeout time.Duration
+	// StreamTimeout bounds a streamed book list, including the time the
+	// client takes to read it; zero means no limit.
+	StreamTimeout time.Duration
+	// The book change listener retries a lost connection after
+	// ListenerMinReconnect, doubling the wait up to ListenerMaxReconnect.
+	ListenerMinReconnect time.Duration
+	ListenerMaxReconnect time.Duration
 }
 
 func NewDBConfig() DBConfig {
 	return DBConfig{
-		Host:     GetEnv("DB_HOST", "localhost"),
-		User:     GetEnv("DB_USER", "postgres"),
-		Password: GetEnv("DB_PASSWORD", ""),
-		DBName:   GetEnv("DB_NAME", "library"),
-		SSLMode:  GetEnv("DB_SSLMODE", "disable"),
+		Host:                 GetEnv("DB_HOST", "localhost"),
+		User:                 GetEnv("DB_USER", "postgres"),
+		Password:             GetEnv("DB_PASSWORD", ""),
+		DBName:               GetEnv("DB_NAME", "library"),
+		SSLMode:              GetEnv("DB_SSLMODE", "disable"),
+		QueryTimeout:         GetEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+		StreamTimeout:        GetEnvDuration("DB_STREAM_TIMEOUT", 5*time.Minute),
+		ListenerMinReconnect: GetEnvDuration("DB_LISTENER_MIN_RECONNECT", time.Second),
+		ListenerMaxReconnect: GetEnvDuration("DB_LISTENER_MAX_RECONNECT", time.Minute),
 	}
 }
 
@@ -27,3 +43,51 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue
+	}
+	return duration
+}
+
+func GetEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return number
+}
+
+func GetEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	boolean, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return boolean
+}
+
+func GetEnvTime(key string, defaultValue time.Time) time.Time {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
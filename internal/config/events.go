@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type EventStreamConfig struct {
+	ReplaySize        int
+	HeartbeatInterval time.Duration
+}
+
+// NewEventStreamConfig falls back to the default heartbeat interval when the
+// configured one is not positive, and keeps no replay buffer for a negative
+// size.
+func NewEventStreamConfig() EventStreamConfig {
+	config := EventStreamConfig{
+		ReplaySize:        GetEnvInt("EVENT_STREAM_REPLAY_SIZE", 1000),
+		HeartbeatInterval: GetEnvDuration("EVENT_STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = 15 * time.Second
+	}
+	config.ReplaySize = max(config.ReplaySize, 0)
+	return config
+}
@@ -0,0 +1,11 @@
+package config
+
+type GRPCConfig struct {
+	Port string
+}
+
+func NewGRPCConfig() GRPCConfig {
+	return GRPCConfig{
+		Port: GetEnv("GRPC_PORT", "9090"),
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+func NewLogConfig() LogConfig {
+	return LogConfig{
+		Level:  GetEnv("LOG_LEVEL", "info"),
+		Format: GetEnv("LOG_FORMAT", "json"),
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type OutboxConfig struct {
+	Publisher    string
+	WebhookURL   string
+	Channel      string
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+func NewOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		Publisher:    GetEnv("OUTBOX_PUBLISHER", "log"),
+		WebhookURL:   GetEnv("OUTBOX_WEBHOOK_URL", ""),
+		Channel:      GetEnv("OUTBOX_CHANNEL", "library:books:events"),
+		BatchSize:    GetEnvInt("OUTBOX_BATCH_SIZE", 100),
+		PollInterval: GetEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import "strings"
+
+type RateLimitConfig struct {
+	Default string
+	Routes  map[string]string
+}
+
+// NewRateLimitConfig reads limits written as "<rate per second>:<burst>".
+// RATE_LIMIT_ROUTES overrides the default per route, e.g. "/books=5:10,/books/=20:40".
+func NewRateLimitConfig() RateLimitConfig {
+	routes := map[string]string{}
+	for _, rule := range strings.Split(GetEnv("RATE_LIMIT_ROUTES", ""), ",") {
+		route, limit, found := strings.Cut(strings.TrimSpace(rule), "=")
+		if found {
+			routes[route] = limit
+		}
+	}
+	return RateLimitConfig{
+		Default: GetEnv("RATE_LIMIT_DEFAULT", "0:0"),
+		Routes:  routes,
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+type ServerConfig struct {
+	Port                string
+	MaxBodyBytes        int64
+	IdempotencyTTL      time.Duration
+	CompressionMinBytes int
+}
+
+func NewServerConfig() ServerConfig {
+	return ServerConfig{
+		Port:                GetEnv("PORT", "8080"),
+		MaxBodyBytes:        int64(GetEnvInt("HTTP_MAX_BODY_BYTES", 1<<20)),
+		IdempotencyTTL:      GetEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		CompressionMinBytes: GetEnvInt("HTTP_COMPRESSION_MIN_BYTES", 1024),
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+type TLSConfig struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ClientAuth     string
+	ReloadInterval time.Duration
+}
+
+func NewTLSConfig() TLSConfig {
+	return TLSConfig{
+		CertFile:       GetEnv("TLS_CERT_FILE", ""),
+		KeyFile:        GetEnv("TLS_KEY_FILE", ""),
+		ClientCAFile:   GetEnv("TLS_CLIENT_CA_FILE", ""),
+		ClientAuth:     GetEnv("TLS_CLIENT_AUTH", "none"),
+		ReloadInterval: GetEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+	}
+}
+
+func (tlsConfig TLSConfig) Enabled() bool {
+	return tlsConfig.CertFile != "" && tlsConfig.KeyFile != ""
+}
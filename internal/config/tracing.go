@@ -0,0 +1,11 @@
+package config
+
+type TracingConfig struct {
+	Exporter string
+}
+
+func NewTracingConfig() TracingConfig {
+	return TracingConfig{
+		Exporter: GetEnv("TRACING_EXPORTER", "none"),
+	}
+}
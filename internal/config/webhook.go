@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type WebhookConfig struct {
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	Timeout        time.Duration
+	PollInterval   time.Duration
+	BatchSize      int
+}
+
+func NewWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:    GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		RetryBaseDelay: GetEnvDuration("WEBHOOK_RETRY_BASE_DELAY", 10*time.Second),
+		RetryMaxDelay:  GetEnvDuration("WEBHOOK_RETRY_MAX_DELAY", time.Hour),
+		Timeout:        GetEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		PollInterval:   GetEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		BatchSize:      GetEnvInt("WEBHOOK_BATCH_SIZE", 50),
+	}
+}
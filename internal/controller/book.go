@@ -1,112 +1,156 @@
 package controller
 
 import (
-	"encoding/json"
+	"database/sql"
+	"errors"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/codec"
 	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/dto"
+	v1 "gojek/library-service-api/internal/dto/v1"
 	"gojek/library-service-api/internal/repository"
+	"log/slog"
 	"net/http"
+	"path"
 	"strconv"
-	"strings"
+	"time"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client
+// goes away before the query backing its request has finished.
+const StatusClientClosedRequest = 499
+
+var (
+	errInvalidRequestBody = errors.New("invalid request body")
+	errBookNotFound       = errors.New("book not found")
 )
 
 type BookController struct {
-	Repository *repository.BookRepository
+	Repository  repository.BookStore
+	CacheMaxAge time.Duration
 }
 
 func (bookController *BookController) GetAllBooks(w http.ResponseWriter, r *http.Request) {
-	books, _ := bookController.Repository.FindAllBooks()
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string][]domain.Book{"books": books})
+	if streamBooks(w, r, bookController.Repository, bookController.CacheMaxAge, func(book domain.Book) interface{} { return v1.NewBook(book) }) {
+		return
+	}
+	books, err := bookController.Repository.FindAllBooks(r.Context())
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find books", err)
+		return
+	}
+	w.Header().Set("Cache-Control", cacheControl(bookController.CacheMaxAge))
+	writeResponse(w, r, http.StatusOK, v1.NewBookList(books))
 }
 
 func (bookController *BookController) GetBookByID(w http.ResponseWriter, r *http.Request) {
-	id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/books/"))
-	book, err := bookController.Repository.FindBookByID(id)
-	w.Header().Set("Content-Type", "application/json")
+	book, err := bookController.Repository.FindBookByID(r.Context(), bookIDFromPath(r))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		response := struct {
-			Error string `json:"error"`
-		}{Error: "Internal server error."}
-
-		jsonInBytes, _ := json.Marshal(response)
-		w.Write(jsonInBytes)
+		writeErrorResponse(w, r, "failed to find book", err)
+		return
+	}
+	if notModified(w, r, bookController.CacheMaxAge, book.UpdatedAt) {
 		return
 	}
-	json.NewEncoder(w).Encode(book)
+	writeResponse(w, r, http.StatusOK, v1.NewBook(book))
 }
 
 func (bookController *BookController) AddBook(w http.ResponseWriter, r *http.Request) {
-	book := domain.Book{}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewDecoder(r.Body).Decode(&book); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		response := struct {
-			Error string `json:"error"`
-		}{Error: "Internal server error."}
-
-		jsonInBytes, _ := json.Marshal(response)
-		w.Write(jsonInBytes)
+	request := v1.AddBookRequest{}
+	if err := decodeBody(r, &request, false); err != nil {
+		writeErrorResponse(w, r, "invalid add book request", err)
 		return
 	}
 
-	bookController.Repository.SaveBook(&book)
-	bookResponse := map[string]interface{}{
-		"id":            book.ID,
-		"title":         book.Title,
-		"price":         book.Price,
-		"publishedDate": book.PublishedDate,
-		"message":       "Book successfully added to the library.",
+	book := request.ToDomain()
+	if err := bookController.Repository.SaveBook(r.Context(), &book); err != nil {
+		writeErrorResponse(w, r, "failed to save book", err)
+		return
 	}
-	json.NewEncoder(w).Encode(bookResponse)
+	auditLog(r, "book added", book.ID)
+	writeResponse(w, r, http.StatusOK, v1.NewAddBookResponse(book))
 }
 
 func (bookController *BookController) UpdateBookTitle(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/books/"))
-	book, notFoundErr := bookController.Repository.FindBookByID(id)
-	bodyRequest := struct {
-		Title string `json:"title"`
-	}{}
-	invalidRequestErr := json.NewDecoder(r.Body).Decode(&bodyRequest)
-	if notFoundErr != nil || invalidRequestErr != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		response := struct {
-			Error string `json:"error"`
-		}{Error: "Internal server error."}
-
-		jsonInBytes, _ := json.Marshal(response)
-		w.Write(jsonInBytes)
+	book, notFoundErr := bookController.Repository.FindBookByID(r.Context(), bookIDFromPath(r))
+	bodyRequest := v1.UpdateBookTitleRequest{}
+	invalidRequestErr := decodeBody(r, &bodyRequest, false)
+	if err := errors.Join(notFoundErr, invalidRequestErr); err != nil {
+		writeErrorResponse(w, r, "invalid update book title request", err)
 		return
 	}
-	bookController.Repository.UpdateBookTitle(book.ID, bodyRequest.Title)
-	updatedBook, _ := bookController.Repository.FindBookByID(book.ID)
-	bookResponse := map[string]interface{}{
-		"id":      updatedBook.ID,
-		"title":   updatedBook.Title,
-		"message": "Book title successfully updated.",
+	if err := bookController.Repository.UpdateBookTitle(r.Context(), book.ID, bodyRequest.Title); err != nil {
+		writeErrorResponse(w, r, "failed to update book title", err)
+		return
 	}
-	json.NewEncoder(w).Encode(bookResponse)
+	updatedBook, err := bookController.Repository.FindBookByID(r.Context(), book.ID)
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find updated book", err)
+		return
+	}
+	auditLog(r, "book title updated", updatedBook.ID)
+	writeResponse(w, r, http.StatusOK, v1.NewUpdateBookTitleResponse(updatedBook))
 }
 
 func (bookController *BookController) DeleteBookByID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/books/"))
-	book, err := bookController.Repository.FindBookByID(id)
+	book, err := bookController.Repository.FindBookByID(r.Context(), bookIDFromPath(r))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		response := struct {
-			Error string `json:"error"`
-		}{Error: "Internal server error."}
-
-		jsonInBytes, _ := json.Marshal(response)
-		w.Write(jsonInBytes)
+		writeErrorResponse(w, r, "failed to find book to delete", err)
 		return
 	}
-	bookController.Repository.DeleteBookByID(book.ID)
-	bookResponse := map[string]interface{}{
-		"id":      book.ID,
-		"message": "Book successfully deleted.",
+	if err := bookController.Repository.DeleteBookByID(r.Context(), book.ID); err != nil {
+		writeErrorResponse(w, r, "failed to delete book", err)
+		return
 	}
-	json.NewEncoder(w).Encode(bookResponse)
+	auditLog(r, "book deleted", book.ID)
+	writeResponse(w, r, http.StatusOK, v1.NewDeleteBookResponse(book))
+}
+
+// bookIDFromPath reads the ID from the last path segment, so the handlers serve
+// /books/{id} under any version prefix. A malformed ID reads as 0, which no
+// book has.
+func bookIDFromPath(r *http.Request) int {
+	id, _ := strconv.Atoi(path.Base(r.URL.Path))
+	return id
+}
+
+// auditLog records who changed the catalogue, using the principal put on the
+// request context by the authentication middleware.
+func auditLog(r *http.Request, action string, bookID int) {
+	audit(r, action, "book_id", bookID)
+}
+
+func audit(r *http.Request, action string, args ...interface{}) {
+	actor, method := "anonymous", ""
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		actor, method = principal.Subject, principal.Method
+	}
+	slog.InfoContext(r.Context(), action, append(args, "actor", actor, "auth_method", method)...)
+}
+
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, logMessage string, err error) {
+	statusCode, message, logLevel := http.StatusInternalServerError, "Internal server error.", slog.LevelError
+	maxBytesErr := &http.MaxBytesError{}
+	switch {
+	case errors.As(err, &maxBytesErr):
+		statusCode, message, logLevel = http.StatusRequestEntityTooLarge, "Request body too large.", slog.LevelWarn
+	case errors.Is(err, codec.ErrUnsupportedMediaType):
+		statusCode, message, logLevel = http.StatusUnsupportedMediaType, "Unsupported media type.", slog.LevelWarn
+	case errors.Is(err, errInvalidRequestBody):
+		statusCode, message, logLevel = http.StatusBadRequest, "Invalid request body.", slog.LevelWarn
+	case errors.Is(err, errBookNotFound):
+		statusCode, message, logLevel = http.StatusNotFound, "Book not found.", slog.LevelWarn
+	case errors.Is(err, errWebhookNotFound):
+		statusCode, message, logLevel = http.StatusNotFound, "Webhook not found.", slog.LevelWarn
+	case errors.Is(err, repository.ErrQueryCanceled):
+		statusCode, message, logLevel = StatusClientClosedRequest, "Request canceled by client.", slog.LevelWarn
+	case errors.Is(err, repository.ErrQueryTimeout):
+		statusCode, message = http.StatusGatewayTimeout, "Request timed out."
+	case errors.Is(err, sql.ErrNoRows):
+		logLevel = slog.LevelWarn
+	}
+	slog.Log(r.Context(), logLevel, logMessage,
+		"error", err, "method", r.Method, "path", r.URL.Path, "status", statusCode)
+
+	writeJSON(w, r, statusCode, dto.Error{Error: message})
 }
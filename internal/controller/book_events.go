@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"gojek/library-service-api/internal/sse"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// BookEventsController serves GET /books/events, a Server-Sent Events stream
+// of the book changes made through any instance.
+type BookEventsController struct {
+	Broker            *sse.Broker
+	HeartbeatInterval time.Duration
+}
+
+// StreamBookEvents replays the events after Last-Event-ID, or a resync event
+// when they are no longer buffered, and then streams new ones until the client
+// goes away.
+func (bookEventsController *BookEventsController) StreamBookEvents(w http.ResponseWriter, r *http.Request) {
+	missed, events, unsubscribe := bookEventsController.Broker.Subscribe(r.Header.Get("Last-Event-ID"))
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	for _, event := range missed {
+		if err := sse.WriteEvent(w, event); err != nil {
+			return
+		}
+	}
+	responseController := http.NewResponseController(w)
+	responseController.Flush()
+
+	heartbeat := time.NewTicker(bookEventsController.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-events:
+			if !open {
+				slog.InfoContext(r.Context(), "closed slow book events stream", "path", r.URL.Path)
+				return
+			}
+			err = sse.WriteEvent(w, event)
+		case <-heartbeat.C:
+			err = sse.WriteComment(w, "heartbeat")
+		}
+		if err == nil {
+			err = responseController.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package controller_test
+
+import (
+	"bufio"
+	"context"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/sse"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readEventStreamMessage reads one SSE message as its lines, without the
+// blank line that ends it.
+func readEventStreamMessage(t *testing.T, reader *bufio.Reader) []string {
+	t.Helper()
+	lines := []string{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event stream: %v", err)
+		}
+		if line == "\n" {
+			return lines
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func TestStreamBookEvents_GivenUnknownLastEventID_ThenSendResyncEvent(t *testing.T) {
+	bookEventsController := &controller.BookEventsController{Broker: sse.NewBroker(10), HeartbeatInterval: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/books/events", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "unknown-3")
+
+	res := serveContract(t, bookEventsController.StreamBookEvents, req)
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "event: resync\n")
+}
+
+func TestStreamBookEvents_GivenPublishedEvent_ThenStreamItAndHeartbeats(t *testing.T) {
+	broker := sse.NewBroker(10)
+	bookEventsController := &controller.BookEventsController{Broker: broker, HeartbeatInterval: 20 * time.Millisecond}
+	server := httptest.NewServer(http.HandlerFunc(bookEventsController.StreamBookEvents))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/books/events")
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	reader := bufio.NewReader(res.Body)
+
+	assert.Equal(t, []string{": heartbeat"}, readEventStreamMessage(t, reader))
+	broker.BookChanged(domain.Event{Type: domain.EventBookDeleted, BookID: 4, Payload: []byte(`{"id":4}`)})
+	message := readEventStreamMessage(t, reader)
+	for message[0] == ": heartbeat" {
+		message = readEventStreamMessage(t, reader)
+	}
+	assert.Len(t, message, 3)
+	assert.True(t, strings.HasPrefix(message[0], "id: "))
+	assert.Equal(t, "event: BookDeleted", message[1])
+	assert.Contains(t, message[2], `"book_id":4`)
+}
+
+func TestStreamBookEvents_GivenClientDisconnects_ThenUnsubscribe(t *testing.T) {
+	broker := sse.NewBroker(10)
+	bookEventsController := &controller.BookEventsController{Broker: broker, HeartbeatInterval: time.Hour}
+	server := httptest.NewServer(http.HandlerFunc(bookEventsController.StreamBookEvents))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/books/events")
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return broker.Subscribers() == 1 }, time.Second, 5*time.Millisecond)
+	res.Body.Close()
+
+	assert.Eventually(t, func() bool { return broker.Subscribers() == 0 }, time.Second, 5*time.Millisecond)
+}
@@ -2,16 +2,20 @@ package controller_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"gojek/library-service-api/internal/controller"
 	"gojek/library-service-api/internal/domain"
+	v1 "gojek/library-service-api/internal/dto/v1"
 	"gojek/library-service-api/internal/repository"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
@@ -28,8 +32,10 @@ func setupTestDB(t *testing.T) *sql.DB {
             id SERIAL PRIMARY KEY,
             title VARCHAR(100),
             price NUMERIC(10, 2),
-            published_date DATE
+            published_date DATE,
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
         );
+        ALTER TABLE books ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
     `)
 	if err != nil {
 		t.Fatalf("Failed to create books table: %v", err)
@@ -52,17 +58,14 @@ func TestGetAllBooks_GivenNothing_ThenReturnEmptyBooksResponse(t *testing.T) {
 	defer teardown()
 
 	req := httptest.NewRequest(http.MethodGet, "/books", nil)
-	w := httptest.NewRecorder()
-	bookController.GetAllBooks(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.GetAllBooks, req)
 	defer res.Body.Close()
 
 	assert.Equal(t, http.StatusOK, res.StatusCode)
-	response := map[string][]domain.Book{}
+	response := v1.BookList{}
 	err := json.NewDecoder(res.Body).Decode(&response)
 	assert.NoError(t, err)
-	assert.Empty(t, response["books"])
+	assert.Empty(t, response.Books)
 }
 
 func TestGetBookById_GivenExistedBook_ThenReturnCorrespondingBookResponse(t *testing.T) {
@@ -76,14 +79,11 @@ func TestGetBookById_GivenExistedBook_ThenReturnCorrespondingBookResponse(t *tes
 		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
 		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
 	req := httptest.NewRequest(http.MethodGet, "/books/"+strconv.Itoa(book.ID), nil)
-	w := httptest.NewRecorder()
-	bookController.GetBookByID(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.GetBookByID, req)
 	defer res.Body.Close()
 
 	assert.Equal(t, http.StatusOK, res.StatusCode)
-	response := domain.Book{}
+	response := v1.Book{}
 	err := json.NewDecoder(res.Body).Decode(&response)
 	assert.NoError(t, err)
 	assert.Equal(t, "Clean Code", response.Title)
@@ -96,10 +96,7 @@ func TestGetBookById_GivenNotFoundBook_ThenReturnErrorResponse(t *testing.T) {
 	defer teardown()
 
 	req := httptest.NewRequest(http.MethodGet, "/books/"+strconv.Itoa(-1), nil)
-	w := httptest.NewRecorder()
-	bookController.GetBookByID(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.GetBookByID, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
@@ -118,10 +115,7 @@ func TestAddBook_GivenInvalidRequestBody_ThenReturnErrorResponse(t *testing.T) {
 	}{ID: "invalid request"}
 	invalidRequestInJSON, _ := json.Marshal(invalidRequest)
 	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(invalidRequestInJSON))
-	w := httptest.NewRecorder()
-	bookController.AddBook(w, req)
-
-	res := w.Result()
+	res := serveInvalidRequestContract(t, bookController.AddBook, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
@@ -137,13 +131,10 @@ func TestAddBook_GivenValidRequestBody_ThenReturnAddedBookResponse(t *testing.T)
 	bookController, teardown := setupTestController(t)
 	defer teardown()
 
-	book := &domain.Book{Title: "The Great Gatsby", Price: 15.99, PublishedDate: "1925-04-10"}
+	book := v1.AddBookRequest{Title: "The Great Gatsby", Price: 15.99, PublishedDate: "1925-04-10"}
 	bookJSON, _ := json.Marshal(book)
 	req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewReader(bookJSON))
-	w := httptest.NewRecorder()
-	bookController.AddBook(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.AddBook, req)
 	defer res.Body.Close()
 
 	response := map[string]interface{}{}
@@ -164,11 +155,8 @@ func TestUpdateBookTitle_GivenInvalidRequestBody_ThenReturnErrorResponse(t *test
 		Title int `json:"title"`
 	}{Title: 1234}
 	invalidRequestInJSON, _ := json.Marshal(invalidRequest)
-	req := httptest.NewRequest(http.MethodPut, "/books", bytes.NewReader(invalidRequestInJSON))
-	w := httptest.NewRecorder()
-	bookController.UpdateBookTitle(w, req)
-
-	res := w.Result()
+	req := httptest.NewRequest(http.MethodPut, "/books/"+strconv.Itoa(-1), bytes.NewReader(invalidRequestInJSON))
+	res := serveInvalidRequestContract(t, bookController.UpdateBookTitle, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
@@ -186,11 +174,8 @@ func TestUpdateBoookTitle_GivenNotFoundBook_ThenReturnErrorResponse(t *testing.T
 		Title string `json:"title"`
 	}{Title: "Updated Title"}
 	bodyRequestInJSON, _ := json.Marshal(bodyRequest)
-	req := httptest.NewRequest(http.MethodGet, "/books/"+strconv.Itoa(-1), bytes.NewReader(bodyRequestInJSON))
-	w := httptest.NewRecorder()
-	bookController.UpdateBookTitle(w, req)
-
-	res := w.Result()
+	req := httptest.NewRequest(http.MethodPut, "/books/"+strconv.Itoa(-1), bytes.NewReader(bodyRequestInJSON))
+	res := serveContract(t, bookController.UpdateBookTitle, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
@@ -216,10 +201,7 @@ func TestUpdateBookTitle_GivenValidRequestBodyAndExistedBook_ThenReturnUpdatedBo
 	}{Title: "Updated Title"}
 	bookJSON, _ := json.Marshal(bookTitleUpdate)
 	req := httptest.NewRequest(http.MethodPut, "/books/"+strconv.Itoa(book.ID), bytes.NewReader(bookJSON))
-	w := httptest.NewRecorder()
-	bookController.UpdateBookTitle(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.UpdateBookTitle, req)
 	defer res.Body.Close()
 
 	response := map[string]interface{}{}
@@ -237,10 +219,7 @@ func TestDeleteBookById_GivenNotFoundBook_ThenReturnErrorResponse(t *testing.T)
 	defer teardown()
 
 	req := httptest.NewRequest(http.MethodDelete, "/books/"+strconv.Itoa(-1), nil)
-	w := httptest.NewRecorder()
-	bookController.DeleteBookByID(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.DeleteBookByID, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
@@ -262,10 +241,7 @@ func TestDeleteBookById_GivenExistedBook_ThenReturnDeletedBookResponse(t *testin
 		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
 
 	req := httptest.NewRequest(http.MethodDelete, "/books/"+strconv.Itoa(book.ID), nil)
-	w := httptest.NewRecorder()
-	bookController.DeleteBookByID(w, req)
-
-	res := w.Result()
+	res := serveContract(t, bookController.DeleteBookByID, req)
 	defer res.Body.Close()
 
 	response := map[string]interface{}{}
@@ -274,3 +250,84 @@ func TestDeleteBookById_GivenExistedBook_ThenReturnDeletedBookResponse(t *testin
 	assert.Equal(t, "Book successfully deleted.", response["message"])
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 }
+
+func TestGetAllBooks_GivenCanceledRequest_ThenReturnClientClosedRequestResponse(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	bookController := &controller.BookController{Repository: &repository.BookRepository{DB: db}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/books", nil).WithContext(ctx)
+	res := serveContract(t, bookController.GetAllBooks, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	expectedResponse := `{"error":"Request canceled by client."}`
+
+	assert.Equal(t, expectedResponse, string(data))
+	assert.Equal(t, controller.StatusClientClosedRequest, res.StatusCode)
+}
+
+func TestGetBookById_GivenQueryTimeout_ThenReturnGatewayTimeoutResponse(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	bookRepository := &repository.BookRepository{DB: db, QueryTimeout: time.Nanosecond}
+	bookController := &controller.BookController{Repository: bookRepository}
+
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	res := serveContract(t, bookController.GetBookByID, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	expectedResponse := `{"error":"Request timed out."}`
+
+	assert.Equal(t, expectedResponse, string(data))
+	assert.Equal(t, http.StatusGatewayTimeout, res.StatusCode)
+}
+
+func TestAddBook_GivenCSVRequestBodyAndCanceledRequest_ThenReturnClientClosedRequestResponse(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	bookController := &controller.BookController{Repository: &repository.BookRepository{DB: db}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body := "title,price,publishedDate\r\nDune,9.99,1965-08-01\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set("Content-Type", "text/csv")
+	res := serveContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, controller.StatusClientClosedRequest, res.StatusCode)
+}
+
+func TestAddBook_GivenJSONBodyWithoutContentTypeAndCanceledRequest_ThenDecodeItAsJSON(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	bookController := &controller.BookController{Repository: &repository.BookRepository{DB: db}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body := `{"title":"Dune","price":9.99,"publishedDate":"1965-08-01"}`
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body)).WithContext(ctx)
+	res := serveContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, controller.StatusClientClosedRequest, res.StatusCode)
+}
+
+func TestAddBook_GivenUnsupportedContentType_ThenReturnUnsupportedMediaType(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	bookController := &controller.BookController{Repository: &repository.BookRepository{DB: db}}
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader("title=Dune"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := serveInvalidRequestContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, `{"error":"Unsupported media type."}`, string(data))
+	assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+}
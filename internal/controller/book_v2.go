@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gojek/library-service-api/internal/codec"
+	"gojek/library-service-api/internal/domain"
+	v2 "gojek/library-service-api/internal/dto/v2"
+	"gojek/library-service-api/internal/repository"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BookControllerV2 serves /v2/books. Unlike v1 it answers 400 for malformed
+// bodies and 404 for unknown books, and returns the book itself on success.
+type BookControllerV2 struct {
+	Repository  repository.BookStore
+	CacheMaxAge time.Duration
+}
+
+func (bookController *BookControllerV2) GetAllBooks(w http.ResponseWriter, r *http.Request) {
+	if streamBooks(w, r, bookController.Repository, bookController.CacheMaxAge, func(book domain.Book) interface{} { return v2.NewBook(book) }) {
+		return
+	}
+	books, err := bookController.Repository.FindAllBooks(r.Context())
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find books", err)
+		return
+	}
+	w.Header().Set("Cache-Control", cacheControl(bookController.CacheMaxAge))
+	writeResponse(w, r, http.StatusOK, v2.NewBookList(books))
+}
+
+func (bookController *BookControllerV2) GetBookByID(w http.ResponseWriter, r *http.Request) {
+	book, err := bookController.Repository.FindBookByID(r.Context(), bookIDFromPath(r))
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find book", notFoundError(err))
+		return
+	}
+	if notModified(w, r, bookController.CacheMaxAge, book.UpdatedAt) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, v2.NewBook(book))
+}
+
+func (bookController *BookControllerV2) AddBook(w http.ResponseWriter, r *http.Request) {
+	request := v2.CreateBookRequest{}
+	if err := decodeRequestBody(r, &request); err != nil {
+		writeErrorResponse(w, r, "invalid add book request", err)
+		return
+	}
+
+	book := request.ToDomain()
+	if err := bookController.Repository.SaveBook(r.Context(), &book); err != nil {
+		writeErrorResponse(w, r, "failed to save book", err)
+		return
+	}
+	auditLog(r, "book added", book.ID)
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+strconv.Itoa(book.ID))
+	writeResponse(w, r, http.StatusCreated, v2.NewBook(book))
+}
+
+func (bookController *BookControllerV2) UpdateBookTitle(w http.ResponseWriter, r *http.Request) {
+	request := v2.UpdateBookRequest{}
+	if err := decodeRequestBody(r, &request); err != nil {
+		writeErrorResponse(w, r, "invalid update book title request", err)
+		return
+	}
+	book, err := bookController.Repository.FindBookByID(r.Context(), bookIDFromPath(r))
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find book to update", notFoundError(err))
+		return
+	}
+	if err := bookController.Repository.UpdateBookTitle(r.Context(), book.ID, request.Title); err != nil {
+		writeErrorResponse(w, r, "failed to update book title", err)
+		return
+	}
+	updatedBook, err := bookController.Repository.FindBookByID(r.Context(), book.ID)
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find updated book", err)
+		return
+	}
+	auditLog(r, "book title updated", updatedBook.ID)
+	writeResponse(w, r, http.StatusOK, v2.NewBook(updatedBook))
+}
+
+func (bookController *BookControllerV2) DeleteBookByID(w http.ResponseWriter, r *http.Request) {
+	book, err := bookController.Repository.FindBookByID(r.Context(), bookIDFromPath(r))
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find book to delete", notFoundError(err))
+		return
+	}
+	if err := bookController.Repository.DeleteBookByID(r.Context(), book.ID); err != nil {
+		writeErrorResponse(w, r, "failed to delete book", err)
+		return
+	}
+	auditLog(r, "book deleted", book.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeRequestBody rejects unknown fields and trailing data, and reports
+// malformed or invalid bodies as errInvalidRequestBody.
+func decodeRequestBody(r *http.Request, request interface{ Validate() error }) error {
+	if err := decodeBody(r, request, true); err != nil {
+		maxBytesErr := &http.MaxBytesError{}
+		if errors.As(err, &maxBytesErr) || errors.Is(err, codec.ErrUnsupportedMediaType) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", errInvalidRequestBody, err)
+	}
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidRequestBody, err)
+	}
+	return nil
+}
+
+func notFoundError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", errBookNotFound, err)
+	}
+	return err
+}
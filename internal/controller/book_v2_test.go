@@ -0,0 +1,217 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"gojek/library-service-api/internal/codec"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/domain"
+	v2 "gojek/library-service-api/internal/dto/v2"
+	"gojek/library-service-api/internal/repository"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTestControllerV2(t *testing.T) (*controller.BookControllerV2, *sql.DB) {
+	db := setupTestDB(t)
+	return &controller.BookControllerV2{Repository: &repository.BookRepository{DB: db}}, db
+}
+
+func newUnconnectedControllerV2() (*controller.BookControllerV2, func()) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	return &controller.BookControllerV2{Repository: &repository.BookRepository{DB: db}}, func() { db.Close() }
+}
+
+func TestAddBookV2_GivenValidRequestBody_ThenReturnCreatedBook(t *testing.T) {
+	bookController, db := setupTestControllerV2(t)
+	defer db.Close()
+
+	body := `{"title":"The Great Gatsby","price":15.99,"publishedDate":"1925-04-10"}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/books", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+
+	response := v2.Book{}
+	err := json.NewDecoder(res.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "The Great Gatsby", response.Title)
+	assert.Equal(t, "/v2/books/"+strconv.Itoa(response.ID), res.Header.Get("Location"))
+
+	db.Exec("DELETE FROM books WHERE id = $1", response.ID)
+}
+
+func TestAddBookV2_GivenUnknownField_ThenReturnBadRequest(t *testing.T) {
+	bookController, teardown := newUnconnectedControllerV2()
+	defer teardown()
+
+	body := `{"id":3,"title":"The Great Gatsby","price":15.99,"publishedDate":"1925-04-10"}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/books", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveInvalidRequestContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, `{"error":"Invalid request body."}`, string(data))
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestAddBookV2_GivenCanceledRequest_ThenReturnClientClosedRequestResponse(t *testing.T) {
+	bookController, teardown := newUnconnectedControllerV2()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body := `{"title":"The Great Gatsby","price":15.99,"publishedDate":"1925-04-10"}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/books", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	res := serveContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, controller.StatusClientClosedRequest, res.StatusCode)
+}
+
+func TestUpdateBookTitleV2_GivenEmptyTitle_ThenReturnBadRequest(t *testing.T) {
+	bookController, teardown := newUnconnectedControllerV2()
+	defer teardown()
+
+	req := httptest.NewRequest(http.MethodPut, "/v2/books/1", strings.NewReader(`{"title":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveInvalidRequestContract(t, bookController.UpdateBookTitle, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestGetBookByIdV2_GivenNotFoundBook_ThenReturnNotFound(t *testing.T) {
+	bookController, db := setupTestControllerV2(t)
+	defer db.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/books/"+strconv.Itoa(-1), nil)
+	res := serveContract(t, bookController.GetBookByID, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, `{"error":"Book not found."}`, string(data))
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestDeleteBookByIdV2_GivenExistedBook_ThenReturnNoContent(t *testing.T) {
+	bookController, db := setupTestControllerV2(t)
+	defer db.Close()
+
+	book := &domain.Book{Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"}
+	db.QueryRow(
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
+		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
+
+	req := httptest.NewRequest(http.MethodDelete, "/v2/books/"+strconv.Itoa(book.ID), nil)
+	res := serveContract(t, bookController.DeleteBookByID, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Empty(t, data)
+}
+
+func TestGetAllBooksV2_GivenXMLAccept_ThenReturnXMLBookList(t *testing.T) {
+	bookController, db := setupTestControllerV2(t)
+	defer db.Close()
+
+	book := &domain.Book{Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"}
+	db.QueryRow(
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
+		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/books", nil)
+	req.Header.Set("Accept", "application/xml")
+	res := serveContract(t, bookController.GetAllBooks, req)
+	defer res.Body.Close()
+
+	response := v2.BookList{}
+	err := xml.NewDecoder(res.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/xml; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.NotEmpty(t, response.Books)
+
+	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
+}
+
+func TestAddBookV2_GivenUnsupportedContentType_ThenReturnUnsupportedMediaType(t *testing.T) {
+	bookController, teardown := newUnconnectedControllerV2()
+	defer teardown()
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/books", strings.NewReader("title=Dune"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := serveInvalidRequestContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, `{"error":"Unsupported media type."}`, string(data))
+	assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+}
+
+func TestAddBookV2_GivenXMLBodyWithEmptyTitle_ThenReturnBadRequest(t *testing.T) {
+	bookController, teardown := newUnconnectedControllerV2()
+	defer teardown()
+
+	body := `<book><title></title><price>15.99</price><publishedDate>1925-04-10</publishedDate></book>`
+	req := httptest.NewRequest(http.MethodPost, "/v2/books", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/xml")
+	res := serveContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestAddBookV2_GivenMessagePackBodyAndCanceledRequest_ThenReturnClientClosedRequestResponse(t *testing.T) {
+	bookController, teardown := newUnconnectedControllerV2()
+	defer teardown()
+
+	body := bytes.Buffer{}
+	codec.MessagePack.Encode(&body, v2.CreateBookRequest{Title: "Dune", Price: 9.99, PublishedDate: "1965-08-01"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/v2/books", &body).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/msgpack")
+	res := serveContract(t, bookController.AddBook, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, controller.StatusClientClosedRequest, res.StatusCode)
+}
+
+func TestGetAllBooksV2_GivenNDJSONAccept_ThenStreamOneBookPerLine(t *testing.T) {
+	bookController, db := setupTestControllerV2(t)
+	defer db.Close()
+
+	book := &domain.Book{Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"}
+	db.QueryRow(
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
+		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/books", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := serveContract(t, bookController.GetAllBooks, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		response := v2.Book{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &response))
+	}
+
+	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
+}
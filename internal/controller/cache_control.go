@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// cacheControl lets clients reuse catalogue reads for maxAge. Responses are
+// private because they may depend on the caller's credentials; a zero maxAge
+// makes clients revalidate every time.
+func cacheControl(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "private, no-cache"
+	}
+	return "private, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+}
+
+// notModified sets the caching headers of a book read and reports whether
+// the copy the client dated with If-Modified-Since is still current, in which
+// case it has answered 304.
+func notModified(w http.ResponseWriter, r *http.Request, maxAge time.Duration, lastModified time.Time) bool {
+	w.Header().Set("Cache-Control", cacheControl(maxAge))
+	if lastModified.IsZero() {
+		return false
+	}
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified.After(since) {
+		return false
+	}
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,86 @@
+package controller_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var cachedBookUpdatedAt = time.Date(2026, 3, 1, 10, 30, 15, 500, time.UTC)
+
+// newCachedControllerV2 serves book 1 from the cache; any query would fail
+// because requests are sent with a canceled context.
+func newCachedControllerV2() (*controller.BookControllerV2, func()) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	bookCache := cache.NewMemoryCache(10, 0)
+	cachedBook, _ := json.Marshal(domain.Book{ID: 1, Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01", UpdatedAt: cachedBookUpdatedAt})
+	bookCache.Set(context.Background(), "book:1", cachedBook)
+	bookStore := &repository.CachedBookStore{BookStore: &repository.BookRepository{DB: db}, Cache: bookCache}
+	return &controller.BookControllerV2{Repository: bookStore, CacheMaxAge: time.Minute}, func() { db.Close() }
+}
+
+func newCanceledRequest(method, target string) *http.Request {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return httptest.NewRequest(method, target, nil).WithContext(ctx)
+}
+
+func TestGetBookByIdV2_GivenCachedBook_ThenReturnCacheHeaders(t *testing.T) {
+	bookController, teardown := newCachedControllerV2()
+	defer teardown()
+
+	res := serveContract(t, bookController.GetBookByID, newCanceledRequest(http.MethodGet, "/v2/books/1"))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "private, max-age=60", res.Header.Get("Cache-Control"))
+	assert.Equal(t, "Sun, 01 Mar 2026 10:30:15 GMT", res.Header.Get("Last-Modified"))
+}
+
+func TestGetBookByIdV2_GivenIfModifiedSinceNotBeforeLastChange_ThenReturnNotModified(t *testing.T) {
+	bookController, teardown := newCachedControllerV2()
+	defer teardown()
+
+	req := newCanceledRequest(http.MethodGet, "/v2/books/1")
+	req.Header.Set("If-Modified-Since", "Sun, 01 Mar 2026 10:30:15 GMT")
+	res := serveContract(t, bookController.GetBookByID, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+	assert.Equal(t, "Sun, 01 Mar 2026 10:30:15 GMT", res.Header.Get("Last-Modified"))
+	assert.Empty(t, res.Header.Get("Content-Type"))
+}
+
+func TestGetBookByIdV2_GivenIfModifiedSinceBeforeLastChange_ThenReturnBook(t *testing.T) {
+	bookController, teardown := newCachedControllerV2()
+	defer teardown()
+
+	req := newCanceledRequest(http.MethodGet, "/v2/books/1")
+	req.Header.Set("If-Modified-Since", "Sun, 01 Mar 2026 10:30:14 GMT")
+	res := serveContract(t, bookController.GetBookByID, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestGetBookById_GivenZeroCacheMaxAge_ThenRequireRevalidation(t *testing.T) {
+	bookControllerV2, teardown := newCachedControllerV2()
+	defer teardown()
+	bookController := &controller.BookController{Repository: bookControllerV2.Repository}
+
+	res := serveContract(t, bookController.GetBookByID, newCanceledRequest(http.MethodGet, "/books/1"))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "private, no-cache", res.Header.Get("Cache-Control"))
+}
@@ -0,0 +1,61 @@
+package controller_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/openapi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var contractValidator, contractValidatorErr = openapi.NewValidator(openapi.Document)
+
+// serveContract runs handler and fails the test when the request or the
+// response does not match the service's OpenAPI document.
+func serveContract(t *testing.T, handler http.HandlerFunc, req *http.Request) *http.Response {
+	t.Helper()
+	if err := validateContractRequest(req); err != nil {
+		t.Errorf("Request does not match OpenAPI document: %v", err)
+	}
+	return serveAndValidateResponse(t, handler, req)
+}
+
+// serveInvalidRequestContract is serveContract for tests that deliberately
+// send a request outside the contract; the response must still match it.
+func serveInvalidRequestContract(t *testing.T, handler http.HandlerFunc, req *http.Request) *http.Response {
+	t.Helper()
+	if err := validateContractRequest(req); err == nil {
+		t.Errorf("Expected request to violate OpenAPI document: %s %s", req.Method, req.URL.Path)
+	}
+	return serveAndValidateResponse(t, handler, req)
+}
+
+func validateContractRequest(req *http.Request) error {
+	body := []byte{}
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	// The controllers decode a body without Content-Type as JSON.
+	if req.Header.Get("Content-Type") == "" && len(body) > 0 {
+		req = req.Clone(req.Context())
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return contractValidator.ValidateRequest(req, body)
+}
+
+func serveAndValidateResponse(t *testing.T, handler http.HandlerFunc, req *http.Request) *http.Response {
+	t.Helper()
+	if contractValidatorErr != nil {
+		t.Fatalf("Failed to load OpenAPI document: %v", contractValidatorErr)
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if err := contractValidator.ValidateResponse(req, w.Code, w.Header(), w.Body.Bytes()); err != nil {
+		t.Errorf("Response does not match OpenAPI document: %v", err)
+	}
+	return w.Result()
+}
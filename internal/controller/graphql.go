@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gojek/library-service-api/internal/dto"
+	"gojek/library-service-api/internal/graph"
+	"gojek/library-service-api/internal/repository"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type GraphQLController struct {
+	Schema     *graphql.Schema
+	Repository repository.BookStore
+}
+
+// HandleGraphQLRequest executes operations sent as JSON over POST, or as
+// query parameters over GET, which only allows queries. Book lookups made by
+// one operation are batched through a loader scoped to the request.
+func (graphQLController *GraphQLController) HandleGraphQLRequest(w http.ResponseWriter, r *http.Request) {
+	request := dto.GraphQLRequest{}
+	ctx := graph.WithBookLoader(r.Context(), graph.NewBookLoader(graphQLController.Repository.FindBooksByIDs))
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		request.Query, request.OperationName = query.Get("query"), query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				writeErrorResponse(w, r, "invalid graphql variables", fmt.Errorf("%w: %w", errInvalidRequestBody, err))
+				return
+			}
+		}
+		ctx = graph.WithQueriesOnly(ctx)
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			maxBytesErr := &http.MaxBytesError{}
+			if !errors.As(err, &maxBytesErr) {
+				err = fmt.Errorf("%w: %w", errInvalidRequestBody, err)
+			}
+			writeErrorResponse(w, r, "invalid graphql request", err)
+			return
+		}
+	default:
+		WriteMethodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+		return
+	}
+	if request.Query == "" {
+		writeErrorResponse(w, r, "invalid graphql request", fmt.Errorf("%w: query is required", errInvalidRequestBody))
+		return
+	}
+
+	response := graphQLController.Schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,91 @@
+package controller_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/graph"
+	"gojek/library-service-api/internal/repository"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGraphQLController(t *testing.T, queryTimeout time.Duration) (*controller.GraphQLController, func()) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	bookRepository := &repository.BookRepository{DB: db, QueryTimeout: queryTimeout}
+	schema, err := graph.NewSchema(&graph.Resolver{Repository: bookRepository, Policy: authz.DefaultPolicy()})
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	return &controller.GraphQLController{Schema: schema, Repository: bookRepository}, func() { db.Close() }
+}
+
+func TestHandleGraphQLRequest_GivenMutationOverGET_ThenReturnMethodNotAllowedError(t *testing.T) {
+	graphQLController, teardown := newGraphQLController(t, 0)
+	defer teardown()
+
+	query := url.Values{"query": {`mutation { deleteBook(id: "1") { id } }`}}
+	req := httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+	res := serveContract(t, graphQLController.HandleGraphQLRequest, req)
+	defer res.Body.Close()
+
+	response := struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}{}
+	err := json.NewDecoder(res.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Len(t, response.Errors, 1)
+	assert.Equal(t, "METHOD_NOT_ALLOWED", response.Errors[0].Extensions["code"])
+}
+
+func TestHandleGraphQLRequest_GivenQueryTimeout_ThenReturnTimeoutErrorInBody(t *testing.T) {
+	graphQLController, teardown := newGraphQLController(t, time.Nanosecond)
+	defer teardown()
+
+	body := `{"query":"query Page($first: Int) { books(first: $first) { totalCount } }","variables":{"first":5}}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveContract(t, graphQLController.HandleGraphQLRequest, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(data), `"code":"TIMEOUT"`)
+}
+
+func TestHandleGraphQLRequest_GivenMalformedBody_ThenReturnBadRequest(t *testing.T) {
+	graphQLController, teardown := newGraphQLController(t, 0)
+	defer teardown()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":`))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveInvalidRequestContract(t, graphQLController.HandleGraphQLRequest, req)
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Equal(t, `{"error":"Invalid request body."}`, string(data))
+}
+
+func TestHandleGraphQLRequest_GivenNoQuery_ThenReturnBadRequest(t *testing.T) {
+	graphQLController, teardown := newGraphQLController(t, 0)
+	defer teardown()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"operationName":"Page"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveInvalidRequestContract(t, graphQLController.HandleGraphQLRequest, req)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
@@ -11,11 +11,8 @@ import (
 )
 
 func TestHandleHealthCheckRequest_GivenNothing_ThenReturnHTTPSuccess(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
-	w := httptest.NewRecorder()
-	controller.HandleHealthCheckRequest(w, req)
-
-	res := w.Result()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	res := serveContract(t, controller.HandleHealthCheckRequest, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
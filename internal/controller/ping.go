@@ -1,17 +1,10 @@
 package controller
 
 import (
-	"encoding/json"
+	"gojek/library-service-api/internal/dto"
 	"net/http"
 )
 
 func HandlePingRequest(w http.ResponseWriter, r *http.Request) {
-	response := struct {
-		Message string `json:"message"`
-	}{Message: "pong"}
-
-	jsonInBytes, _ := json.Marshal(response)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(jsonInBytes)
+	writeJSON(w, r, http.StatusOK, dto.Message{Message: "pong"})
 }
@@ -12,10 +12,7 @@ import (
 
 func TestHandlePingRequest_GivenNothing_ThenReturnHTTPSuccess(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
-	w := httptest.NewRecorder()
-	controller.HandlePingRequest(w, req)
-
-	res := w.Result()
+	res := serveContract(t, controller.HandlePingRequest, req)
 	defer res.Body.Close()
 	data, _ := io.ReadAll(res.Body)
 
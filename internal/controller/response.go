@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"gojek/library-service-api/internal/codec"
+	"gojek/library-service-api/internal/dto"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// writeResponse encodes body in the media type negotiated from the Accept
+// header, answering 406 when none of the supported types is acceptable. The
+// body is encoded before anything is written, so a body that cannot be
+// encoded becomes a 500 instead of a success status followed by a truncated
+// payload.
+func writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, body interface{}) {
+	w.Header().Add("Vary", "Accept")
+	responseCodec, err := codec.Negotiate(r.Header.Get("Accept"))
+	if err != nil {
+		slog.WarnContext(r.Context(), "no acceptable media type",
+			"accept", r.Header.Get("Accept"), "method", r.Method, "path", r.URL.Path)
+		writeJSON(w, r, http.StatusNotAcceptable, dto.Error{Error: "Not acceptable."})
+		return
+	}
+
+	data := bytes.Buffer{}
+	if err := responseCodec.Encode(&data, body); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response",
+			"error", err, "method", r.Method, "path", r.URL.Path, "status", statusCode)
+		writeJSON(w, r, http.StatusInternalServerError, dto.Error{Error: "Internal server error."})
+		return
+	}
+	write(w, r, statusCode, responseCodec.ContentType(), data.Bytes())
+}
+
+// writeJSON writes bodies that are only offered as JSON: errors, messages and
+// health responses.
+func writeJSON(w http.ResponseWriter, r *http.Request, statusCode int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response",
+			"error", err, "method", r.Method, "path", r.URL.Path, "status", statusCode)
+		statusCode = http.StatusInternalServerError
+		data, _ = json.Marshal(dto.Error{Error: "Internal server error."})
+	}
+	write(w, r, statusCode, codec.JSON.ContentType(), data)
+}
+
+func write(w http.ResponseWriter, r *http.Request, statusCode int, contentType string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(data); err != nil {
+		slog.WarnContext(r.Context(), "failed to write response",
+			"error", err, "method", r.Method, "path", r.URL.Path, "status", statusCode)
+	}
+}
+
+// decodeBody reads the request body in the media type named by its
+// Content-Type, reporting an unsupported type as codec.ErrUnsupportedMediaType.
+func decodeBody(r *http.Request, request interface{}, disallowUnknownFields bool) error {
+	requestCodec, err := codec.ForContentType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return requestCodec.Decode(data, request, disallowUnknownFields)
+}
+
+// WriteMethodNotAllowed answers 405 in the same error envelope as the
+// controllers, listing the supported methods in the Allow header.
+func WriteMethodNotAllowed(w http.ResponseWriter, r *http.Request, allowedMethods ...string) {
+	w.Header().Set("Allow", strings.Join(allowedMethods, ", "))
+	writeJSON(w, r, http.StatusMethodNotAllowed, dto.Error{Error: "Method not allowed."})
+}
@@ -0,0 +1,26 @@
+package controller_test
+
+import (
+	"gojek/library-service-api/internal/controller"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMethodNotAllowed_GivenAllowedMethods_ThenReturnErrorEnvelopeAndAllowHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/books", nil)
+	w := httptest.NewRecorder()
+	controller.WriteMethodNotAllowed(w, req, http.MethodGet, http.MethodPost)
+
+	res := w.Result()
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal(t, "GET, POST", res.Header.Get("Allow"))
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Equal(t, `{"error":"Method not allowed."}`, string(data))
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"encoding/json"
+	"gojek/library-service-api/internal/codec"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// streamBooks writes the catalogue while it is read from the database when
+// the negotiated media type is JSON or NDJSON, so memory does not grow with
+// the number of books, and reports whether it handled the request. Other media
+// types are left to the caller, which encodes a complete list.
+//
+// Errors before the first byte get the usual error response. After that the
+// status has been sent, so the connection is aborted to keep a truncated body
+// from looking complete.
+func streamBooks(w http.ResponseWriter, r *http.Request, bookStore repository.BookStore, cacheMaxAge time.Duration, newItem func(domain.Book) interface{}) bool {
+	responseCodec, err := codec.Negotiate(r.Header.Get("Accept"))
+	if err != nil || (responseCodec != codec.JSON && responseCodec != codec.NDJSON) {
+		return false
+	}
+
+	stream := &bookListStream{w: w, codec: responseCodec, cacheMaxAge: cacheMaxAge, newItem: newItem}
+	err = bookStore.StreamAllBooks(r.Context(), stream.write)
+	if err == nil {
+		err = stream.close()
+	}
+	if err == nil {
+		return true
+	}
+	if !stream.started {
+		writeErrorResponse(w, r, "failed to find books", err)
+		return true
+	}
+	slog.ErrorContext(r.Context(), "failed to stream books",
+		"error", err, "method", r.Method, "path", r.URL.Path, "books_written", stream.written)
+	panic(http.ErrAbortHandler)
+}
+
+// bookListStream frames books as the elements of the {"books": [...]}
+// envelope, or as one line each for NDJSON.
+type bookListStream struct {
+	w           http.ResponseWriter
+	codec       codec.Codec
+	cacheMaxAge time.Duration
+	newItem     func(domain.Book) interface{}
+	started     bool
+	written     int
+}
+
+func (stream *bookListStream) write(book domain.Book) error {
+	data, err := json.Marshal(stream.newItem(book))
+	if err != nil {
+		return err
+	}
+	separator := ""
+	if stream.codec == codec.NDJSON {
+		data = append(data, '\n')
+	} else if stream.written > 0 {
+		separator = ","
+	}
+	if err := stream.start(); err != nil {
+		return err
+	}
+	if _, err := stream.w.Write(append([]byte(separator), data...)); err != nil {
+		return err
+	}
+	stream.written++
+	return nil
+}
+
+func (stream *bookListStream) close() error {
+	if err := stream.start(); err != nil {
+		return err
+	}
+	if stream.codec == codec.JSON {
+		_, err := stream.w.Write([]byte("]}"))
+		return err
+	}
+	return nil
+}
+
+func (stream *bookListStream) start() error {
+	if stream.started {
+		return nil
+	}
+	stream.started = true
+	stream.w.Header().Add("Vary", "Accept")
+	stream.w.Header().Set("Content-Type", stream.codec.ContentType())
+	stream.w.Header().Set("Cache-Control", cacheControl(stream.cacheMaxAge))
+	stream.w.WriteHeader(http.StatusOK)
+	if stream.codec == codec.JSON {
+		_, err := stream.w.Write([]byte(`{"books":[`))
+		return err
+	}
+	return nil
+}
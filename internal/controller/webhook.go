@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/dto"
+	"gojek/library-service-api/internal/webhook"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// deliveryListLimit caps the deliveries returned for a webhook; they are
+// listed newest first.
+const deliveryListLimit = 100
+
+var errWebhookNotFound = errors.New("webhook not found")
+
+// WebhookController serves /webhooks, where administrators manage the
+// subscriptions the webhook dispatcher delivers book events to.
+type WebhookController struct {
+	Store webhook.Store
+}
+
+func (webhookController *WebhookController) GetAllWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := webhookController.Store.ListSubscriptions(r.Context())
+	if err != nil {
+		writeErrorResponse(w, r, "failed to list webhooks", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, dto.NewWebhookList(subscriptions))
+}
+
+func (webhookController *WebhookController) AddWebhook(w http.ResponseWriter, r *http.Request) {
+	request := dto.CreateWebhookRequest{}
+	if err := decodeRequestBody(r, &request); err != nil {
+		writeErrorResponse(w, r, "invalid add webhook request", err)
+		return
+	}
+
+	subscription := request.ToDomain()
+	if subscription.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			writeErrorResponse(w, r, "failed to generate webhook secret", err)
+			return
+		}
+		subscription.Secret = secret
+	}
+	if err := webhookController.Store.CreateSubscription(r.Context(), &subscription); err != nil {
+		writeErrorResponse(w, r, "failed to save webhook", err)
+		return
+	}
+	audit(r, "webhook added", "webhook_id", subscription.ID)
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+strconv.Itoa(subscription.ID))
+	writeJSON(w, r, http.StatusCreated, dto.CreatedWebhook{Webhook: dto.NewWebhook(subscription), Secret: subscription.Secret})
+}
+
+func (webhookController *WebhookController) GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	subscription, err := webhookController.Store.FindSubscription(r.Context(), webhookIDFromPath(r))
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find webhook", webhookNotFoundError(err))
+		return
+	}
+	writeJSON(w, r, http.StatusOK, dto.NewWebhook(subscription))
+}
+
+func (webhookController *WebhookController) DeleteWebhookByID(w http.ResponseWriter, r *http.Request) {
+	id := webhookIDFromPath(r)
+	if err := webhookController.Store.DeleteSubscription(r.Context(), id); err != nil {
+		writeErrorResponse(w, r, "failed to delete webhook", webhookNotFoundError(err))
+		return
+	}
+	audit(r, "webhook deleted", "webhook_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (webhookController *WebhookController) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookController.listDeliveries(w, r, "")
+}
+
+// GetWebhookDeadLetters lists the deliveries that ran out of attempts.
+func (webhookController *WebhookController) GetWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	webhookController.listDeliveries(w, r, domain.DeliveryDead)
+}
+
+func (webhookController *WebhookController) listDeliveries(w http.ResponseWriter, r *http.Request, status string) {
+	subscription, err := webhookController.Store.FindSubscription(r.Context(), webhookIDFromPath(r))
+	if err != nil {
+		writeErrorResponse(w, r, "failed to find webhook", webhookNotFoundError(err))
+		return
+	}
+	deliveries, err := webhookController.Store.ListDeliveries(r.Context(), subscription.ID, status, deliveryListLimit)
+	if err != nil {
+		writeErrorResponse(w, r, "failed to list webhook deliveries", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, dto.NewWebhookDeliveryList(deliveries))
+}
+
+// webhookIDFromPath reads the ID from the segment after /webhooks/, so it
+// serves both /webhooks/{id} and its subresources. A malformed ID reads as 0,
+// which no webhook has.
+func webhookIDFromPath(r *http.Request) int {
+	_, rest, _ := strings.Cut(r.URL.Path, "/webhooks/")
+	segment, _, _ := strings.Cut(rest, "/")
+	id, _ := strconv.Atoi(segment)
+	return id
+}
+
+func webhookNotFoundError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", errWebhookNotFound, err)
+	}
+	return err
+}
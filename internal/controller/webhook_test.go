@@ -0,0 +1,127 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"gojek/library-service-api/internal/controller"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/dto"
+	"gojek/library-service-api/internal/webhook"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWebhookController(t *testing.T) (*controller.WebhookController, *webhook.MemoryStore, domain.WebhookSubscription) {
+	store := webhook.NewMemoryStore()
+	subscription := domain.WebhookSubscription{URL: "https://partner.example/hooks", Secret: "top-secret-value",
+		Events: []string{domain.EventBookCreated}}
+	if err := store.CreateSubscription(context.Background(), &subscription); err != nil {
+		t.Fatalf("Failed to create subscription: %v", err)
+	}
+	return &controller.WebhookController{Store: store}, store, subscription
+}
+
+func TestAddWebhook_GivenNoSecret_ThenReturnCreatedWebhookWithGeneratedSecret(t *testing.T) {
+	webhookController, store, _ := newWebhookController(t)
+
+	body := `{"url":"https://partner.example/books","events":["BookDeleted"]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveContract(t, webhookController.AddWebhook, req)
+	defer res.Body.Close()
+
+	response := dto.CreatedWebhook{}
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "/webhooks/"+strconv.Itoa(response.ID), res.Header.Get("Location"))
+	assert.Len(t, response.Secret, 64)
+	saved, _ := store.FindSubscription(context.Background(), response.ID)
+	assert.Equal(t, response.Secret, saved.Secret)
+	assert.Equal(t, []string{domain.EventBookDeleted}, saved.Events)
+}
+
+func TestAddWebhook_GivenUnknownEventType_ThenReturnBadRequest(t *testing.T) {
+	webhookController, _, _ := newWebhookController(t)
+
+	body := `{"url":"https://partner.example/books","events":["BookBorrowed"]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := serveInvalidRequestContract(t, webhookController.AddWebhook, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestGetAllWebhooks_GivenSubscription_ThenListItWithoutSecret(t *testing.T) {
+	webhookController, _, subscription := newWebhookController(t)
+
+	res := serveContract(t, webhookController.GetAllWebhooks, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	defer res.Body.Close()
+
+	body := map[string][]map[string]interface{}{}
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Len(t, body["webhooks"], 1)
+	assert.Equal(t, subscription.URL, body["webhooks"][0]["url"])
+	assert.NotContains(t, body["webhooks"][0], "secret")
+}
+
+func TestGetWebhookByID_GivenUnknownWebhook_ThenReturnNotFound(t *testing.T) {
+	webhookController, _, _ := newWebhookController(t)
+
+	res := serveContract(t, webhookController.GetWebhookByID, httptest.NewRequest(http.MethodGet, "/webhooks/99", nil))
+	defer res.Body.Close()
+
+	response := dto.Error{}
+	json.NewDecoder(res.Body).Decode(&response)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, "Webhook not found.", response.Error)
+}
+
+func TestDeleteWebhookByID_GivenExistingWebhook_ThenReturnNoContent(t *testing.T) {
+	webhookController, store, subscription := newWebhookController(t)
+
+	path := "/webhooks/" + strconv.Itoa(subscription.ID)
+	res := serveContract(t, webhookController.DeleteWebhookByID, httptest.NewRequest(http.MethodDelete, path, nil))
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	subscriptions, _ := store.ListSubscriptions(context.Background())
+	assert.Empty(t, subscriptions)
+}
+
+func TestGetWebhookDeadLetters_GivenDeadAndPendingDeliveries_ThenListOnlyDeadOnes(t *testing.T) {
+	webhookController, store, subscription := newWebhookController(t)
+	ctx := context.Background()
+	store.EnqueueDeliveries(ctx, []domain.WebhookDelivery{
+		{SubscriptionID: subscription.ID, EventID: 1, EventType: domain.EventBookCreated, Payload: []byte(`{}`)},
+		{SubscriptionID: subscription.ID, EventID: 2, EventType: domain.EventBookCreated, Payload: []byte(`{}`)},
+	})
+	deliveries, _ := store.ListDeliveries(ctx, subscription.ID, "", 10)
+	dead := deliveries[0]
+	dead.Status, dead.Attempts, dead.LastError, dead.ResponseStatus = domain.DeliveryDead, 8, "receiver answered 500", 500
+	store.UpdateDelivery(ctx, dead)
+
+	path := "/webhooks/" + strconv.Itoa(subscription.ID) + "/dead-letters"
+	res := serveContract(t, webhookController.GetWebhookDeadLetters, httptest.NewRequest(http.MethodGet, path, nil))
+	defer res.Body.Close()
+
+	response := dto.WebhookDeliveryList{}
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Len(t, response.Deliveries, 1)
+	assert.Equal(t, int64(2), response.Deliveries[0].EventID)
+	assert.Equal(t, "receiver answered 500", response.Deliveries[0].LastError)
+}
+
+func TestGetWebhookDeliveries_GivenUnknownWebhook_ThenReturnNotFound(t *testing.T) {
+	webhookController, _, _ := newWebhookController(t)
+
+	res := serveContract(t, webhookController.GetWebhookDeliveries,
+		httptest.NewRequest(http.MethodGet, "/webhooks/99/deliveries", nil))
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
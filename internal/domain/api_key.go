@@ -0,0 +1,9 @@
+package domain
+
+type APIKey struct {
+	ID      int
+	Name    string
+	KeyHash string
+	Subject string
+	Roles   []string
+}
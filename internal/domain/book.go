@@ -1,8 +1,33 @@
 package domain
 
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+const MaxTitleLength = 100
+
+var (
+	ErrTitleRequired = errors.New("title is required")
+	ErrTitleTooLong  = errors.New("title is longer than 100 characters")
+)
+
 type Book struct {
-	ID            int     `json:"id"`
-	Title         string  `json:"title"`
-	Price         float64 `json:"price"`
-	PublishedDate string  `json:"publishedDate"`
+	ID            int
+	Title         string
+	Price         float64
+	PublishedDate string
+	UpdatedAt     time.Time
+}
+
+// ValidateTitle enforces what the books.title column can hold.
+func ValidateTitle(title string) error {
+	switch {
+	case title == "":
+		return ErrTitleRequired
+	case utf8.RuneCountInString(title) > MaxTitleLength:
+		return ErrTitleTooLong
+	}
+	return nil
 }
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+const (
+	EventBookCreated = "BookCreated"
+	EventBookUpdated = "BookUpdated"
+	EventBookDeleted = "BookDeleted"
+)
+
+// Event records a change to a book. Payload is a JSON document describing the
+// book after the change. IDs increase with every event, so consumers can use
+// them to drop the duplicates at-least-once delivery allows.
+type Event struct {
+	ID         int64
+	Type       string
+	BookID     int
+	Payload    []byte
+	OccurredAt time.Time
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+var (
+	ErrWebhookURLInvalid   = errors.New("webhook url must be an absolute http or https url")
+	ErrEventTypeUnknown    = errors.New("unknown event type")
+	ErrWebhookSecretLength = errors.New("webhook secret must be at least 16 characters")
+)
+
+// WebhookSubscription receives the events named in Events, or every event
+// when Events is empty, signed with Secret.
+type WebhookSubscription struct {
+	ID        int
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+func (subscription WebhookSubscription) Wants(eventType string) bool {
+	if len(subscription.Events) == 0 {
+		return true
+	}
+	for _, wanted := range subscription.Events {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event on its way to one subscription. Pending
+// deliveries are attempted at NextAttemptAt; those that run out of attempts
+// become dead letters.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int
+	EventID        int64
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	ResponseStatus int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrWebhookURLInvalid
+	}
+	return nil
+}
+
+func ValidateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		switch eventType {
+		case EventBookCreated, EventBookUpdated, EventBookDeleted:
+		default:
+			return ErrEventTypeUnknown
+		}
+	}
+	return nil
+}
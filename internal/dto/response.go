@@ -0,0 +1,19 @@
+// Package dto holds the response bodies shared by every API version; the
+// version-specific bodies live in its v1 and v2 subpackages.
+package dto
+
+type Error struct {
+	Error string `json:"error"`
+}
+
+type Message struct {
+	Message string `json:"message"`
+}
+
+// GraphQLRequest is the body of POST /graphql, as in the GraphQL over HTTP
+// specification.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
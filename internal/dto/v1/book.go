@@ -0,0 +1,92 @@
+// Package v1 holds the request and response bodies of the first version of
+// the books API, which unversioned paths keep serving.
+package v1
+
+import (
+	"encoding/xml"
+	"gojek/library-service-api/internal/domain"
+)
+
+type Book struct {
+	XMLName       xml.Name `json:"-" xml:"book"`
+	ID            int      `json:"id" xml:"id"`
+	Title         string   `json:"title" xml:"title"`
+	Price         float64  `json:"price" xml:"price"`
+	PublishedDate string   `json:"publishedDate" xml:"publishedDate"`
+}
+
+type BookList struct {
+	XMLName xml.Name `json:"-" xml:"books"`
+	Books   []Book   `json:"books" xml:"book"`
+}
+
+// AddBookRequest accepts an id for compatibility with clients that post a
+// whole book; it is ignored because the database assigns the ID.
+type AddBookRequest struct {
+	XMLName       xml.Name `json:"-" xml:"book"`
+	ID            int      `json:"id" xml:"id"`
+	Title         string   `json:"title" xml:"title"`
+	Price         float64  `json:"price" xml:"price"`
+	PublishedDate string   `json:"publishedDate" xml:"publishedDate"`
+}
+
+type AddBookResponse struct {
+	XMLName       xml.Name `json:"-" xml:"book"`
+	ID            int      `json:"id" xml:"id"`
+	Title         string   `json:"title" xml:"title"`
+	Price         float64  `json:"price" xml:"price"`
+	PublishedDate string   `json:"publishedDate" xml:"publishedDate"`
+	Message       string   `json:"message" xml:"message"`
+}
+
+type UpdateBookTitleRequest struct {
+	XMLName xml.Name `json:"-" xml:"book"`
+	Title   string   `json:"title" xml:"title"`
+}
+
+type UpdateBookTitleResponse struct {
+	XMLName xml.Name `json:"-" xml:"book"`
+	ID      int      `json:"id" xml:"id"`
+	Title   string   `json:"title" xml:"title"`
+	Message string   `json:"message" xml:"message"`
+}
+
+type DeleteBookResponse struct {
+	XMLName xml.Name `json:"-" xml:"book"`
+	ID      int      `json:"id" xml:"id"`
+	Message string   `json:"message" xml:"message"`
+}
+
+func NewBook(book domain.Book) Book {
+	return Book{ID: book.ID, Title: book.Title, Price: book.Price, PublishedDate: book.PublishedDate}
+}
+
+func NewBookList(books []domain.Book) BookList {
+	bookList := BookList{Books: make([]Book, 0, len(books))}
+	for _, book := range books {
+		bookList.Books = append(bookList.Books, NewBook(book))
+	}
+	return bookList
+}
+
+func (request AddBookRequest) ToDomain() domain.Book {
+	return domain.Book{Title: request.Title, Price: request.Price, PublishedDate: request.PublishedDate}
+}
+
+func NewAddBookResponse(book domain.Book) AddBookResponse {
+	return AddBookResponse{
+		ID:            book.ID,
+		Title:         book.Title,
+		Price:         book.Price,
+		PublishedDate: book.PublishedDate,
+		Message:       "Book successfully added to the library.",
+	}
+}
+
+func NewUpdateBookTitleResponse(book domain.Book) UpdateBookTitleResponse {
+	return UpdateBookTitleResponse{ID: book.ID, Title: book.Title, Message: "Book title successfully updated."}
+}
+
+func NewDeleteBookResponse(book domain.Book) DeleteBookResponse {
+	return DeleteBookResponse{ID: book.ID, Message: "Book successfully deleted."}
+}
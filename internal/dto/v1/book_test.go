@@ -0,0 +1,23 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"gojek/library-service-api/internal/domain"
+	v1 "gojek/library-service-api/internal/dto/v1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBookList_GivenNoBooks_ThenEncodeEmptyArray(t *testing.T) {
+	data, err := json.Marshal(v1.NewBookList(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"books":[]}`, string(data))
+}
+
+func TestAddBookRequest_GivenID_ThenIgnoreItWhenMappingToDomain(t *testing.T) {
+	request := v1.AddBookRequest{ID: 7, Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"}
+
+	assert.Equal(t, domain.Book{Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"}, request.ToDomain())
+}
@@ -0,0 +1,58 @@
+// Package v2 holds the request and response bodies of the second version of
+// the books API. Successful responses carry the resource itself rather than
+// a message, and the status code tells what happened.
+package v2
+
+import (
+	"encoding/xml"
+	"gojek/library-service-api/internal/domain"
+)
+
+type Book struct {
+	XMLName       xml.Name `json:"-" xml:"book"`
+	ID            int      `json:"id" xml:"id"`
+	Title         string   `json:"title" xml:"title"`
+	Price         float64  `json:"price" xml:"price"`
+	PublishedDate string   `json:"publishedDate" xml:"publishedDate"`
+}
+
+type BookList struct {
+	XMLName xml.Name `json:"-" xml:"books"`
+	Books   []Book   `json:"books" xml:"book"`
+}
+
+type CreateBookRequest struct {
+	XMLName       xml.Name `json:"-" xml:"book"`
+	Title         string   `json:"title" xml:"title"`
+	Price         float64  `json:"price" xml:"price"`
+	PublishedDate string   `json:"publishedDate" xml:"publishedDate"`
+}
+
+type UpdateBookRequest struct {
+	XMLName xml.Name `json:"-" xml:"book"`
+	Title   string   `json:"title" xml:"title"`
+}
+
+func NewBook(book domain.Book) Book {
+	return Book{ID: book.ID, Title: book.Title, Price: book.Price, PublishedDate: book.PublishedDate}
+}
+
+func NewBookList(books []domain.Book) BookList {
+	bookList := BookList{Books: make([]Book, 0, len(books))}
+	for _, book := range books {
+		bookList.Books = append(bookList.Books, NewBook(book))
+	}
+	return bookList
+}
+
+func (request CreateBookRequest) ToDomain() domain.Book {
+	return domain.Book{Title: request.Title, Price: request.Price, PublishedDate: request.PublishedDate}
+}
+
+func (request CreateBookRequest) Validate() error {
+	return domain.ValidateTitle(request.Title)
+}
+
+func (request UpdateBookRequest) Validate() error {
+	return domain.ValidateTitle(request.Title)
+}
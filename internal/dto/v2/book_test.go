@@ -0,0 +1,26 @@
+package v2_test
+
+import (
+	"gojek/library-service-api/internal/domain"
+	v2 "gojek/library-service-api/internal/dto/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBookRequestValidate_GivenTitle_ThenReturnNil(t *testing.T) {
+	request := v2.CreateBookRequest{Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"}
+
+	assert.NoError(t, request.Validate())
+}
+
+func TestCreateBookRequestValidate_GivenEmptyTitle_ThenReturnErrTitleRequired(t *testing.T) {
+	assert.ErrorIs(t, v2.CreateBookRequest{}.Validate(), domain.ErrTitleRequired)
+}
+
+func TestUpdateBookRequestValidate_GivenTooLongTitle_ThenReturnErrTitleTooLong(t *testing.T) {
+	request := v2.UpdateBookRequest{Title: strings.Repeat("é", domain.MaxTitleLength+1)}
+
+	assert.ErrorIs(t, request.Validate(), domain.ErrTitleTooLong)
+}
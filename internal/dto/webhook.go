@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"gojek/library-service-api/internal/domain"
+	"time"
+)
+
+// minWebhookSecretLength keeps chosen secrets long enough that signatures
+// cannot be forged by guessing them.
+const minWebhookSecretLength = 16
+
+// CreateWebhookRequest subscribes URL to the listed event types, or to every
+// event when Events is empty. The service generates a secret when none is
+// given.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreatedWebhook is the only response that carries the signing secret.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type WebhookList struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type WebhookDelivery struct {
+	ID             int64     `json:"id"`
+	EventID        int64     `json:"eventId"`
+	EventType      string    `json:"eventType"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"lastError"`
+	ResponseStatus int       `json:"responseStatus"`
+	NextAttemptAt  time.Time `json:"nextAttemptAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+func (request CreateWebhookRequest) Validate() error {
+	if err := domain.ValidateWebhookURL(request.URL); err != nil {
+		return err
+	}
+	if request.Secret != "" && len(request.Secret) < minWebhookSecretLength {
+		return domain.ErrWebhookSecretLength
+	}
+	return domain.ValidateEventTypes(request.Events)
+}
+
+func (request CreateWebhookRequest) ToDomain() domain.WebhookSubscription {
+	events := request.Events
+	if events == nil {
+		events = []string{}
+	}
+	return domain.WebhookSubscription{URL: request.URL, Secret: request.Secret, Events: events}
+}
+
+func NewWebhook(subscription domain.WebhookSubscription) Webhook {
+	events := subscription.Events
+	if events == nil {
+		events = []string{}
+	}
+	return Webhook{ID: subscription.ID, URL: subscription.URL, Events: events, CreatedAt: subscription.CreatedAt}
+}
+
+func NewWebhookList(subscriptions []domain.WebhookSubscription) WebhookList {
+	webhookList := WebhookList{Webhooks: make([]Webhook, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		webhookList.Webhooks = append(webhookList.Webhooks, NewWebhook(subscription))
+	}
+	return webhookList
+}
+
+func NewWebhookDeliveryList(deliveries []domain.WebhookDelivery) WebhookDeliveryList {
+	deliveryList := WebhookDeliveryList{Deliveries: make([]WebhookDelivery, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		deliveryList.Deliveries = append(deliveryList.Deliveries, WebhookDelivery{ID: delivery.ID,
+			EventID: delivery.EventID, EventType: delivery.EventType, Status: delivery.Status,
+			Attempts: delivery.Attempts, LastError: delivery.LastError, ResponseStatus: delivery.ResponseStatus,
+			NextAttemptAt: delivery.NextAttemptAt, CreatedAt: delivery.CreatedAt, UpdatedAt: delivery.UpdatedAt})
+	}
+	return deliveryList
+}
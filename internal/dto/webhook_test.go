@@ -0,0 +1,31 @@
+package dto_test
+
+import (
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhookRequestValidate_GivenURLAndKnownEvents_ThenReturnNil(t *testing.T) {
+	request := dto.CreateWebhookRequest{URL: "https://partner.example/hooks", Events: []string{domain.EventBookCreated}}
+
+	assert.NoError(t, request.Validate())
+}
+
+func TestCreateWebhookRequestValidate_GivenRelativeURL_ThenReturnErrWebhookURLInvalid(t *testing.T) {
+	assert.ErrorIs(t, dto.CreateWebhookRequest{URL: "/hooks"}.Validate(), domain.ErrWebhookURLInvalid)
+}
+
+func TestCreateWebhookRequestValidate_GivenUnknownEvent_ThenReturnErrEventTypeUnknown(t *testing.T) {
+	request := dto.CreateWebhookRequest{URL: "https://partner.example/hooks", Events: []string{"book.borrowed"}}
+
+	assert.ErrorIs(t, request.Validate(), domain.ErrEventTypeUnknown)
+}
+
+func TestCreateWebhookRequestValidate_GivenShortSecret_ThenReturnErrWebhookSecretLength(t *testing.T) {
+	request := dto.CreateWebhookRequest{URL: "https://partner.example/hooks", Secret: "short"}
+
+	assert.ErrorIs(t, request.Validate(), domain.ErrWebhookSecretLength)
+}
@@ -0,0 +1,44 @@
+// Package eventbus hands the book changes made through any instance, as
+// announced by the database, to the subscribers in this process.
+package eventbus
+
+import (
+	"gojek/library-service-api/internal/domain"
+	"sync"
+)
+
+// Subscriber is called on the publishing goroutine, so it must not block.
+type Subscriber interface {
+	BookChanged(event domain.Event)
+	// ChangesMissed is called when changes may have gone undelivered, e.g.
+	// while the connection to the database was down.
+	ChangesMissed()
+}
+
+// Bus is ready to use as its zero value.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []Subscriber
+}
+
+func (bus *Bus) Subscribe(subscriber Subscriber) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.subscribers = append(bus.subscribers, subscriber)
+}
+
+func (bus *Bus) Publish(event domain.Event) {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	for _, subscriber := range bus.subscribers {
+		subscriber.BookChanged(event)
+	}
+}
+
+func (bus *Bus) Resync() {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	for _, subscriber := range bus.subscribers {
+		subscriber.ChangesMissed()
+	}
+}
@@ -0,0 +1,45 @@
+package eventbus_test
+
+import (
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/eventbus"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSubscriber struct {
+	events  []domain.Event
+	resyncs int
+}
+
+func (subscriber *recordingSubscriber) BookChanged(event domain.Event) {
+	subscriber.events = append(subscriber.events, event)
+}
+
+func (subscriber *recordingSubscriber) ChangesMissed() {
+	subscriber.resyncs++
+}
+
+func TestPublish_GivenSubscribers_ThenDeliverEventToEach(t *testing.T) {
+	bus := &eventbus.Bus{}
+	first, second := &recordingSubscriber{}, &recordingSubscriber{}
+	bus.Subscribe(first)
+	bus.Subscribe(second)
+
+	bus.Publish(domain.Event{Type: domain.EventBookDeleted, BookID: 4})
+
+	assert.Equal(t, []domain.Event{{Type: domain.EventBookDeleted, BookID: 4}}, first.events)
+	assert.Equal(t, first.events, second.events)
+}
+
+func TestResync_GivenSubscriber_ThenReportMissedChanges(t *testing.T) {
+	bus := &eventbus.Bus{}
+	subscriber := &recordingSubscriber{}
+	bus.Subscribe(subscriber)
+
+	bus.Resync()
+
+	assert.Equal(t, 1, subscriber.resyncs)
+	assert.Empty(t, subscriber.events)
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"gojek/library-service-api/internal/domain"
+	"sync"
+	"time"
+)
+
+// BookLoader batches the book lookups made while resolving one request: IDs
+// requested within Wait of the first one are fetched with a single call, and
+// each ID is fetched at most once per loader. Book has no nested relations yet,
+// so what it batches are sibling lookups such as aliased book fields.
+type BookLoader struct {
+	Fetch func(ctx context.Context, ids []int) ([]domain.Book, error)
+	Wait  time.Duration
+
+	mutex   sync.Mutex
+	pending *bookBatch
+	batches map[int]*bookBatch
+}
+
+func NewBookLoader(fetch func(ctx context.Context, ids []int) ([]domain.Book, error)) *BookLoader {
+	return &BookLoader{Fetch: fetch, Wait: time.Millisecond}
+}
+
+type bookBatch struct {
+	ids   []int
+	books map[int]domain.Book
+	err   error
+	done  chan struct{}
+}
+
+// Load returns the book with id, or false when it does not exist.
+func (loader *BookLoader) Load(ctx context.Context, id int) (domain.Book, bool, error) {
+	loader.mutex.Lock()
+	batch, requested := loader.batches[id]
+	if !requested {
+		if loader.pending == nil {
+			loader.pending = &bookBatch{done: make(chan struct{})}
+			// The batch serves every caller, so one caller going away must
+			// not cancel the others' lookups.
+			batchCtx := context.WithoutCancel(ctx)
+			time.AfterFunc(loader.Wait, func() { loader.dispatch(batchCtx) })
+		}
+		batch = loader.pending
+		batch.ids = append(batch.ids, id)
+		if loader.batches == nil {
+			loader.batches = map[int]*bookBatch{}
+		}
+		loader.batches[id] = batch
+	}
+	loader.mutex.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return domain.Book{}, false, ctx.Err()
+	}
+	book, found := batch.books[id]
+	return book, found, batch.err
+}
+
+func (loader *BookLoader) dispatch(ctx context.Context) {
+	loader.mutex.Lock()
+	batch := loader.pending
+	loader.pending = nil
+	loader.mutex.Unlock()
+
+	books, err := loader.Fetch(ctx, batch.ids)
+	batch.books, batch.err = make(map[int]domain.Book, len(books)), err
+	for _, book := range books {
+		batch.books[book.ID] = book
+	}
+	close(batch.done)
+}
+
+type loaderKey struct{}
+
+func WithBookLoader(ctx context.Context, loader *BookLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+func bookLoaderFromContext(ctx context.Context) (*BookLoader, bool) {
+	loader, ok := ctx.Value(loaderKey{}).(*BookLoader)
+	return loader, ok
+}
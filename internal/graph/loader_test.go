@@ -0,0 +1,110 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/graph"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBookFetcher struct {
+	mutex   sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (fetcher *fakeBookFetcher) Fetch(ctx context.Context, ids []int) ([]domain.Book, error) {
+	fetcher.mutex.Lock()
+	defer fetcher.mutex.Unlock()
+	fetcher.batches = append(fetcher.batches, append([]int{}, ids...))
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	books := []domain.Book{}
+	for _, id := range ids {
+		if id > 0 {
+			books = append(books, domain.Book{ID: id, Title: "Book " + string(rune('A'+id-1))})
+		}
+	}
+	return books, fetcher.err
+}
+
+func TestBookLoaderLoad_GivenConcurrentLoads_ThenFetchOnceWithAllIDs(t *testing.T) {
+	fetcher := &fakeBookFetcher{}
+	loader := &graph.BookLoader{Fetch: fetcher.Fetch, Wait: 10 * time.Millisecond}
+
+	var waitGroup sync.WaitGroup
+	for _, id := range []int{1, 2, 3, 2} {
+		waitGroup.Add(1)
+		go func(id int) {
+			defer waitGroup.Done()
+			book, found, err := loader.Load(context.Background(), id)
+			assert.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, id, book.ID)
+		}(id)
+	}
+	waitGroup.Wait()
+
+	assert.Len(t, fetcher.batches, 1)
+	sort.Ints(fetcher.batches[0])
+	assert.Equal(t, []int{1, 2, 3}, fetcher.batches[0])
+}
+
+func TestBookLoaderLoad_GivenAlreadyLoadedID_ThenDoNotFetchAgain(t *testing.T) {
+	fetcher := &fakeBookFetcher{}
+	loader := graph.NewBookLoader(fetcher.Fetch)
+
+	loader.Load(context.Background(), 1)
+	book, found, err := loader.Load(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Book A", book.Title)
+	assert.Len(t, fetcher.batches, 1)
+}
+
+func TestBookLoaderLoad_GivenMissingBook_ThenReturnNotFound(t *testing.T) {
+	loader := graph.NewBookLoader((&fakeBookFetcher{}).Fetch)
+
+	_, found, err := loader.Load(context.Background(), -1)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestBookLoaderLoad_GivenFetchError_ThenReturnItToEveryCaller(t *testing.T) {
+	fetchErr := errors.New("connection reset")
+	loader := graph.NewBookLoader((&fakeBookFetcher{err: fetchErr}).Fetch)
+
+	_, _, err := loader.Load(context.Background(), 1)
+
+	assert.ErrorIs(t, err, fetchErr)
+}
+
+func TestBookLoaderLoad_GivenFirstCallerCanceled_ThenStillLoadForOtherCallers(t *testing.T) {
+	fetcher := &fakeBookFetcher{}
+	loader := &graph.BookLoader{Fetch: fetcher.Fetch, Wait: 20 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	canceled := make(chan error)
+	go func() {
+		_, _, err := loader.Load(ctx, 1)
+		canceled <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	book, found, err := loader.Load(context.Background(), 2)
+
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 2, book.ID)
+	assert.Len(t, fetcher.batches, 1)
+}
@@ -0,0 +1,208 @@
+// Package graph serves the books catalogue as a GraphQL schema whose
+// resolvers use the same repository and authorization policy as the REST API.
+package graph
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"log/slog"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+const (
+	maxPageSize   = 100
+	maxQueryDepth = 10
+)
+
+type Resolver struct {
+	Repository repository.BookStore
+	Policy     *authz.Policy
+}
+
+func NewSchema(resolver *Resolver) (*graphql.Schema, error) {
+	return graphql.ParseSchema(Schema, resolver, graphql.MaxDepth(maxQueryDepth))
+}
+
+type bookFilterInput struct {
+	TitleContains   *string
+	MinPrice        *float64
+	MaxPrice        *float64
+	PublishedAfter  *string
+	PublishedBefore *string
+}
+
+type addBookInput struct {
+	Title         string
+	Price         float64
+	PublishedDate string
+}
+
+func (resolver *Resolver) Book(ctx context.Context, args struct{ ID graphql.ID }) (*bookResolver, error) {
+	if err := resolver.authorize(ctx, authz.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+	id, err := strconv.Atoi(string(args.ID))
+	if err != nil {
+		return nil, nil
+	}
+	book, found, err := resolver.loader(ctx).Load(ctx, id)
+	if err != nil {
+		return nil, repositoryError(ctx, "failed to load book", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &bookResolver{book: book}, nil
+}
+
+func (resolver *Resolver) Books(ctx context.Context, args struct {
+	Filter *bookFilterInput
+	First  int32
+	Offset int32
+}) (*bookPageResolver, error) {
+	if err := resolver.authorize(ctx, authz.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+	filter := repository.BookFilter{Limit: int(args.First), Offset: int(args.Offset)}
+	if filter.Limit < 0 || filter.Limit > maxPageSize || filter.Offset < 0 {
+		return nil, &Error{Message: "first must be between 0 and " + strconv.Itoa(maxPageSize) + " and offset must not be negative.", Code: "BAD_USER_INPUT"}
+	}
+	if args.Filter != nil {
+		filter.MinPrice, filter.MaxPrice = args.Filter.MinPrice, args.Filter.MaxPrice
+		filter.TitleContains = valueOrEmpty(args.Filter.TitleContains)
+		filter.PublishedAfter = valueOrEmpty(args.Filter.PublishedAfter)
+		filter.PublishedBefore = valueOrEmpty(args.Filter.PublishedBefore)
+	}
+
+	books, total, err := resolver.Repository.SearchBooks(ctx, filter)
+	if err != nil {
+		return nil, repositoryError(ctx, "failed to search books", err)
+	}
+	return &bookPageResolver{books: books, total: total, hasNextPage: filter.Offset+len(books) < total}, nil
+}
+
+func (resolver *Resolver) AddBook(ctx context.Context, args struct{ Input addBookInput }) (*bookResolver, error) {
+	if err := resolver.authorizeMutation(ctx, authz.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateTitle(args.Input.Title); err != nil {
+		return nil, &Error{Message: err.Error(), Code: "BAD_USER_INPUT"}
+	}
+	book := domain.Book{Title: args.Input.Title, Price: args.Input.Price, PublishedDate: args.Input.PublishedDate}
+	if err := resolver.Repository.SaveBook(ctx, &book); err != nil {
+		return nil, repositoryError(ctx, "failed to save book", err)
+	}
+	auditLog(ctx, "book added", book.ID)
+	return &bookResolver{book: book}, nil
+}
+
+func (resolver *Resolver) UpdateBookTitle(ctx context.Context, args struct {
+	ID    graphql.ID
+	Title string
+}) (*bookResolver, error) {
+	if err := resolver.authorizeMutation(ctx, authz.PermissionWriteBooks); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateTitle(args.Title); err != nil {
+		return nil, &Error{Message: err.Error(), Code: "BAD_USER_INPUT"}
+	}
+	book, err := resolver.findBook(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolver.Repository.UpdateBookTitle(ctx, book.ID, args.Title); err != nil {
+		return nil, repositoryError(ctx, "failed to update book title", err)
+	}
+	book.Title = args.Title
+	auditLog(ctx, "book title updated", book.ID)
+	return &bookResolver{book: book}, nil
+}
+
+func (resolver *Resolver) DeleteBook(ctx context.Context, args struct{ ID graphql.ID }) (*bookResolver, error) {
+	if err := resolver.authorizeMutation(ctx, authz.PermissionDeleteBooks); err != nil {
+		return nil, err
+	}
+	book, err := resolver.findBook(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolver.Repository.DeleteBookByID(ctx, book.ID); err != nil {
+		return nil, repositoryError(ctx, "failed to delete book", err)
+	}
+	auditLog(ctx, "book deleted", book.ID)
+	return &bookResolver{book: book}, nil
+}
+
+func (resolver *Resolver) findBook(ctx context.Context, id graphql.ID) (domain.Book, error) {
+	bookID, _ := strconv.Atoi(string(id))
+	book, err := resolver.Repository.FindBookByID(ctx, bookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return book, &Error{Message: "Book not found.", Code: "NOT_FOUND"}
+	}
+	if err != nil {
+		return book, repositoryError(ctx, "failed to find book", err)
+	}
+	return book, nil
+}
+
+// loader returns the request's BookLoader, or a fresh one when the schema is
+// executed outside HandleGraphQLRequest.
+func (resolver *Resolver) loader(ctx context.Context) *BookLoader {
+	if loader, ok := bookLoaderFromContext(ctx); ok {
+		return loader
+	}
+	return NewBookLoader(resolver.Repository.FindBooksByIDs)
+}
+
+func (resolver *Resolver) authorizeMutation(ctx context.Context, permission string) error {
+	if queriesOnly, _ := ctx.Value(queriesOnlyKey{}).(bool); queriesOnly {
+		return &Error{Message: "Mutations must be sent with POST.", Code: "METHOD_NOT_ALLOWED"}
+	}
+	return resolver.authorize(ctx, permission)
+}
+
+func (resolver *Resolver) authorize(ctx context.Context, permission string) error {
+	roles, subject := []string{authz.AnonymousRole}, ""
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		roles, subject = principal.Roles, principal.Subject
+	}
+	if !resolver.Policy.Allows(roles, permission) {
+		slog.WarnContext(ctx, "authorization denied", "subject", subject, "roles", roles, "permission", permission)
+		return &Error{Message: "Missing permission: " + permission + ".", Code: "FORBIDDEN",
+			Details: map[string]interface{}{"missingPermission": permission}}
+	}
+	return nil
+}
+
+type queriesOnlyKey struct{}
+
+// WithQueriesOnly makes mutations fail, for operations received over GET.
+func WithQueriesOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queriesOnlyKey{}, true)
+}
+
+func auditLog(ctx context.Context, action string, bookID int) {
+	actor, method := "anonymous", ""
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		actor, method = principal.Subject, principal.Method
+	}
+	slog.InfoContext(ctx, action, "book_id", bookID, "actor", actor, "auth_method", method, "api", "graphql")
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
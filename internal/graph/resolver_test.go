@@ -0,0 +1,106 @@
+package graph_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/graph"
+	"gojek/library-service-api/internal/repository"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSchema(t *testing.T) (*graphql.Schema, func()) {
+	return setupSchemaWithQueryTimeout(t, 0)
+}
+
+func setupSchemaWithQueryTimeout(t *testing.T, queryTimeout time.Duration) (*graphql.Schema, func()) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	bookRepository := &repository.BookRepository{DB: db, QueryTimeout: queryTimeout}
+	schema, err := graph.NewSchema(&graph.Resolver{Repository: bookRepository, Policy: authz.DefaultPolicy()})
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	return schema, func() { db.Close() }
+}
+
+func errorCodes(response *graphql.Response) []interface{} {
+	codes := []interface{}{}
+	for _, queryError := range response.Errors {
+		codes = append(codes, queryError.Extensions["code"])
+	}
+	return codes
+}
+
+func TestExec_GivenAliasedBookFields_ThenBatchLookupsIntoOneFetch(t *testing.T) {
+	schema, teardown := setupSchema(t)
+	defer teardown()
+	fetcher := &fakeBookFetcher{}
+	ctx := graph.WithBookLoader(context.Background(), graph.NewBookLoader(fetcher.Fetch))
+
+	response := schema.Exec(ctx, `{ first: book(id: "1") { title } second: book(id: "2") { title } missing: book(id: "-1") { title } }`, "", nil)
+
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"first":{"title":"Book A"},"second":{"title":"Book B"},"missing":null}`, string(response.Data))
+	assert.Len(t, fetcher.batches, 1)
+	sort.Ints(fetcher.batches[0])
+	assert.Equal(t, []int{-1, 1, 2}, fetcher.batches[0])
+}
+
+func TestExec_GivenAnonymousMutation_ThenReturnForbiddenWithMissingPermission(t *testing.T) {
+	schema, teardown := setupSchema(t)
+	defer teardown()
+
+	response := schema.Exec(context.Background(), `mutation { deleteBook(id: "1") { id } }`, "", nil)
+
+	assert.Equal(t, []interface{}{"FORBIDDEN"}, errorCodes(response))
+	assert.Equal(t, authz.PermissionDeleteBooks, response.Errors[0].Extensions["missingPermission"])
+}
+
+func TestExec_GivenMutationInQueriesOnlyContext_ThenReturnMethodNotAllowed(t *testing.T) {
+	schema, teardown := setupSchema(t)
+	defer teardown()
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Roles: []string{"admin"}})
+
+	response := schema.Exec(graph.WithQueriesOnly(ctx), `mutation { deleteBook(id: "1") { id } }`, "", nil)
+
+	assert.Equal(t, []interface{}{"METHOD_NOT_ALLOWED"}, errorCodes(response))
+}
+
+func TestExec_GivenEmptyTitle_ThenReturnBadUserInput(t *testing.T) {
+	schema, teardown := setupSchema(t)
+	defer teardown()
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Roles: []string{"librarian"}})
+
+	response := schema.Exec(ctx, `mutation { addBook(input: {title: "", price: 1, publishedDate: "2020-01-01"}) { id } }`, "", nil)
+
+	assert.Equal(t, []interface{}{"BAD_USER_INPUT"}, errorCodes(response))
+}
+
+func TestExec_GivenPageSizeAboveMaximum_ThenReturnBadUserInput(t *testing.T) {
+	schema, teardown := setupSchema(t)
+	defer teardown()
+
+	response := schema.Exec(context.Background(), `{ books(first: 1000) { totalCount } }`, "", nil)
+
+	assert.Equal(t, []interface{}{"BAD_USER_INPUT"}, errorCodes(response))
+}
+
+func TestExec_GivenQueryTimeout_ThenReturnTimeoutWithoutDatabaseDetails(t *testing.T) {
+	schema, teardown := setupSchemaWithQueryTimeout(t, time.Nanosecond)
+	defer teardown()
+
+	response := schema.Exec(context.Background(), `query($filter: BookFilter) { books(filter: $filter) { totalCount } }`, "",
+		map[string]interface{}{"filter": map[string]interface{}{"titleContains": "Code"}})
+
+	assert.Equal(t, []interface{}{"TIMEOUT"}, errorCodes(response))
+	data, _ := json.Marshal(response.Errors)
+	assert.NotContains(t, string(data), "postgres")
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"log/slog"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type bookResolver struct {
+	book domain.Book
+}
+
+func (resolver *bookResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(resolver.book.ID))
+}
+
+func (resolver *bookResolver) Title() string {
+	return resolver.book.Title
+}
+
+func (resolver *bookResolver) Price() float64 {
+	return resolver.book.Price
+}
+
+func (resolver *bookResolver) PublishedDate() string {
+	return resolver.book.PublishedDate
+}
+
+type bookPageResolver struct {
+	books       []domain.Book
+	total       int
+	hasNextPage bool
+}
+
+func (resolver *bookPageResolver) Items() []*bookResolver {
+	items := make([]*bookResolver, 0, len(resolver.books))
+	for _, book := range resolver.books {
+		items = append(items, &bookResolver{book: book})
+	}
+	return items
+}
+
+func (resolver *bookPageResolver) TotalCount() int32 {
+	return int32(resolver.total)
+}
+
+func (resolver *bookPageResolver) HasNextPage() bool {
+	return resolver.hasNextPage
+}
+
+// Error is reported in the errors list of the response, with Code and
+// Details under its extensions.
+type Error struct {
+	Message string
+	Code    string
+	Details map[string]interface{}
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+func (err *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": err.Code}
+	for key, value := range err.Details {
+		extensions[key] = value
+	}
+	return extensions
+}
+
+// repositoryError logs err and hides its details from the client, keeping
+// only whether the query was canceled or timed out.
+func repositoryError(ctx context.Context, logMessage string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrQueryCanceled), errors.Is(err, context.Canceled):
+		slog.WarnContext(ctx, logMessage, "error", err)
+		return &Error{Message: "Request canceled by client.", Code: "CANCELED"}
+	case errors.Is(err, repository.ErrQueryTimeout), errors.Is(err, context.DeadlineExceeded):
+		slog.ErrorContext(ctx, logMessage, "error", err)
+		return &Error{Message: "Request timed out.", Code: "TIMEOUT"}
+	}
+	slog.ErrorContext(ctx, logMessage, "error", err)
+	return &Error{Message: "Internal server error.", Code: "INTERNAL"}
+}
@@ -0,0 +1,136 @@
+// Package grpcserver serves library.v1.BookService on top of the same
+// repository, authenticator and policy as the REST API.
+package grpcserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	libraryv1 "gojek/library-service-api/api/library/v1"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"log/slog"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type BookService struct {
+	libraryv1.UnimplementedBookServiceServer
+	Repository repository.BookStore
+}
+
+func (bookService *BookService) ListBooks(ctx context.Context, request *libraryv1.ListBooksRequest) (*libraryv1.ListBooksResponse, error) {
+	filter := repository.BookFilter{Limit: int(request.GetPageSize()), TitleContains: request.GetTitleContains()}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", maxPageSize)
+	}
+	if request.GetPageToken() != "" {
+		offset, err := strconv.Atoi(request.GetPageToken())
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "page_token is invalid")
+		}
+		filter.Offset = offset
+	}
+
+	books, total, err := bookService.Repository.SearchBooks(ctx, filter)
+	if err != nil {
+		return nil, statusError(ctx, "failed to list books", err)
+	}
+	response := &libraryv1.ListBooksResponse{TotalSize: int32(total)}
+	for _, book := range books {
+		response.Books = append(response.Books, newBook(book))
+	}
+	if next := filter.Offset + len(books); next < total {
+		response.NextPageToken = strconv.Itoa(next)
+	}
+	return response, nil
+}
+
+func (bookService *BookService) GetBook(ctx context.Context, request *libraryv1.GetBookRequest) (*libraryv1.Book, error) {
+	book, err := bookService.Repository.FindBookByID(ctx, int(request.GetId()))
+	if err != nil {
+		return nil, statusError(ctx, "failed to find book", err)
+	}
+	return newBook(book), nil
+}
+
+func (bookService *BookService) CreateBook(ctx context.Context, request *libraryv1.CreateBookRequest) (*libraryv1.Book, error) {
+	if err := domain.ValidateTitle(request.GetTitle()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	book := domain.Book{Title: request.GetTitle(), Price: request.GetPrice(), PublishedDate: request.GetPublishedDate()}
+	if err := bookService.Repository.SaveBook(ctx, &book); err != nil {
+		return nil, statusError(ctx, "failed to save book", err)
+	}
+	auditLog(ctx, "book added", book.ID)
+	return newBook(book), nil
+}
+
+func (bookService *BookService) UpdateBook(ctx context.Context, request *libraryv1.UpdateBookRequest) (*libraryv1.Book, error) {
+	if err := domain.ValidateTitle(request.GetTitle()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	book, err := bookService.Repository.FindBookByID(ctx, int(request.GetId()))
+	if err != nil {
+		return nil, statusError(ctx, "failed to find book to update", err)
+	}
+	if err := bookService.Repository.UpdateBookTitle(ctx, book.ID, request.GetTitle()); err != nil {
+		return nil, statusError(ctx, "failed to update book title", err)
+	}
+	book.Title = request.GetTitle()
+	auditLog(ctx, "book title updated", book.ID)
+	return newBook(book), nil
+}
+
+func (bookService *BookService) DeleteBook(ctx context.Context, request *libraryv1.DeleteBookRequest) (*libraryv1.DeleteBookResponse, error) {
+	book, err := bookService.Repository.FindBookByID(ctx, int(request.GetId()))
+	if err != nil {
+		return nil, statusError(ctx, "failed to find book to delete", err)
+	}
+	if err := bookService.Repository.DeleteBookByID(ctx, book.ID); err != nil {
+		return nil, statusError(ctx, "failed to delete book", err)
+	}
+	auditLog(ctx, "book deleted", book.ID)
+	return &libraryv1.DeleteBookResponse{}, nil
+}
+
+func newBook(book domain.Book) *libraryv1.Book {
+	return &libraryv1.Book{Id: int64(book.ID), Title: book.Title, Price: book.Price, PublishedDate: book.PublishedDate}
+}
+
+// statusError logs err and converts it into the status a client can act on,
+// without the database details.
+func statusError(ctx context.Context, logMessage string, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		slog.WarnContext(ctx, logMessage, "error", err)
+		return status.Error(codes.NotFound, "book not found")
+	case errors.Is(err, repository.ErrQueryCanceled):
+		slog.WarnContext(ctx, logMessage, "error", err)
+		return status.Error(codes.Canceled, "request canceled by client")
+	case errors.Is(err, repository.ErrQueryTimeout):
+		slog.ErrorContext(ctx, logMessage, "error", err)
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	}
+	slog.ErrorContext(ctx, logMessage, "error", err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func auditLog(ctx context.Context, action string, bookID int) {
+	actor, method := "anonymous", ""
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		actor, method = principal.Subject, principal.Method
+	}
+	slog.InfoContext(ctx, action, "book_id", bookID, "actor", actor, "auth_method", method, "api", "grpc")
+}
@@ -0,0 +1,53 @@
+package grpcserver_test
+
+import (
+	"context"
+	"database/sql"
+	libraryv1 "gojek/library-service-api/api/library/v1"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateBook_GivenValidRequest_ThenBookCanBeFetched(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, 0))
+
+	created, err := client.CreateBook(withAPIKey("librarian-key"),
+		&libraryv1.CreateBookRequest{Title: "Clean Code", Price: 10.99, PublishedDate: "1990-06-01"})
+	assert.NoError(t, err)
+	fetched, err := client.GetBook(context.Background(), &libraryv1.GetBookRequest{Id: created.GetId()})
+	assert.NoError(t, err)
+	assert.Equal(t, "Clean Code", fetched.GetTitle())
+
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	db.Exec("DELETE FROM books WHERE id = $1", created.GetId())
+}
+
+func TestCreateBook_GivenEmptyTitle_ThenReturnInvalidArgument(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, 0))
+
+	_, err := client.CreateBook(withAPIKey("librarian-key"), &libraryv1.CreateBookRequest{Price: 10.99})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListBooks_GivenInvalidPageToken_ThenReturnInvalidArgument(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, 0))
+
+	_, err := client.ListBooks(context.Background(), &libraryv1.ListBooksRequest{PageToken: "next"})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetBook_GivenQueryTimeout_ThenReturnDeadlineExceeded(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, time.Nanosecond))
+
+	_, err := client.GetBook(context.Background(), &libraryv1.GetBookRequest{Id: 1})
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, "request timed out", status.Convert(err).Message())
+}
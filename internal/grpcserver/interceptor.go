@@ -0,0 +1,104 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	libraryv1 "gojek/library-service-api/api/library/v1"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var methodPermissions = map[string]string{
+	libraryv1.BookService_ListBooks_FullMethodName:  authz.PermissionReadBooks,
+	libraryv1.BookService_GetBook_FullMethodName:    authz.PermissionReadBooks,
+	libraryv1.BookService_CreateBook_FullMethodName: authz.PermissionWriteBooks,
+	libraryv1.BookService_UpdateBook_FullMethodName: authz.PermissionWriteBooks,
+	libraryv1.BookService_DeleteBook_FullMethodName: authz.PermissionDeleteBooks,
+}
+
+// Recover turns a panic in a handler into an Internal status, like the HTTP
+// Recover middleware.
+func Recover(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			slog.ErrorContext(ctx, "panic recovered", "panic", recovered, "method", info.FullMethod, "stack", string(debug.Stack()))
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, request)
+}
+
+// Authenticate resolves the caller of BookService methods from the
+// authorization or x-api-key metadata, or from a verified client certificate.
+// Calls without credentials are rejected unless they read and publicReads is set.
+func Authenticate(authenticator *auth.Authenticator, publicReads bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		permission, protected := methodPermissions[info.FullMethod]
+		if !protected {
+			return handler(ctx, request)
+		}
+
+		principal, err := authenticator.Authenticate(httpRequestFromContext(ctx))
+		switch {
+		case errors.Is(err, auth.ErrMissingCredentials) && publicReads && permission == authz.PermissionReadBooks:
+			return handler(ctx, request)
+		case errors.Is(err, auth.ErrMissingCredentials), errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrInvalidAPIKey):
+			slog.WarnContext(ctx, "authentication failed", "error", err, "method", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, "valid credentials are required")
+		case err != nil:
+			slog.ErrorContext(ctx, "failed to authenticate", "error", err, "method", info.FullMethod)
+			return nil, status.Error(codes.Internal, "unable to verify credentials")
+		}
+		return handler(auth.WithPrincipal(ctx, principal), request)
+	}
+}
+
+// Authorize checks the permission each BookService method requires against
+// the roles of the authenticated principal.
+func Authorize(policy *authz.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		permission, protected := methodPermissions[info.FullMethod]
+		if !protected {
+			return handler(ctx, request)
+		}
+
+		roles, subject := []string{authz.AnonymousRole}, ""
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			roles, subject = principal.Roles, principal.Subject
+		}
+		if !policy.Allows(roles, permission) {
+			slog.WarnContext(ctx, "authorization denied", "subject", subject, "roles", roles, "permission", permission, "method", info.FullMethod)
+			return nil, status.Errorf(codes.PermissionDenied, "missing permission: %s", permission)
+		}
+		return handler(ctx, request)
+	}
+}
+
+// httpRequestFromContext presents the call's metadata and TLS state as an
+// HTTP request so that the REST authenticator can be reused.
+func httpRequestFromContext(ctx context.Context) *http.Request {
+	header := http.Header{}
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range incoming {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	r := (&http.Request{Header: header}).WithContext(ctx)
+	if caller, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := caller.AuthInfo.(credentials.TLSInfo); ok {
+			r.TLS = &tlsInfo.State
+		}
+	}
+	return r
+}
@@ -0,0 +1,41 @@
+package grpcserver_test
+
+import (
+	"context"
+	libraryv1 "gojek/library-service-api/api/library/v1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withAPIKey(apiKey string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", apiKey)
+}
+
+func TestAuthenticate_GivenAnonymousWrite_ThenReturnUnauthenticated(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, 0))
+
+	_, err := client.CreateBook(context.Background(), &libraryv1.CreateBookRequest{Title: "Clean Code"})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthenticate_GivenUnknownAPIKey_ThenReturnUnauthenticated(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, 0))
+
+	_, err := client.GetBook(withAPIKey("stolen-key"), &libraryv1.GetBookRequest{Id: 1})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthorize_GivenMemberDeletingBook_ThenReturnPermissionDeniedNamingPermission(t *testing.T) {
+	client := libraryv1.NewBookServiceClient(startServer(t, 0))
+
+	_, err := client.DeleteBook(withAPIKey("member-key"), &libraryv1.DeleteBookRequest{Id: 1})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "books:delete")
+}
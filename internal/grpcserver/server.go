@@ -0,0 +1,27 @@
+package grpcserver
+
+import (
+	libraryv1 "gojek/library-service-api/api/library/v1"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer returns a gRPC server with BookService behind authentication and
+// authorization, plus the standard health and reflection services.
+func NewServer(bookService *BookService, authenticator *auth.Authenticator, policy *authz.Policy, publicReads bool, options ...grpc.ServerOption) *grpc.Server {
+	options = append(options, grpc.ChainUnaryInterceptor(Recover, Authenticate(authenticator, publicReads), Authorize(policy)))
+	server := grpc.NewServer(options...)
+	libraryv1.RegisterBookServiceServer(server, bookService)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(libraryv1.BookService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	return server
+}
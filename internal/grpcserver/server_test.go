@@ -0,0 +1,91 @@
+package grpcserver_test
+
+import (
+	"context"
+	"database/sql"
+	libraryv1 "gojek/library-service-api/api/library/v1"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/grpcserver"
+	"gojek/library-service-api/internal/repository"
+	"net"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeAPIKeys map[string][]string
+
+func (apiKeys fakeAPIKeys) FindAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	for key, roles := range apiKeys {
+		if auth.HashAPIKey(key) == keyHash {
+			return domain.APIKey{Subject: key, Roles: roles}, nil
+		}
+	}
+	return domain.APIKey{}, sql.ErrNoRows
+}
+
+// startServer serves the gRPC server over an in-process bufconn listener with
+// the API keys "member-key", "librarian-key" and "admin-key".
+func startServer(t *testing.T, queryTimeout time.Duration) *grpc.ClientConn {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	authenticator := &auth.Authenticator{APIKeys: fakeAPIKeys{
+		"member-key":    {"member"},
+		"librarian-key": {"librarian"},
+		"admin-key":     {"admin"},
+	}}
+	bookService := &grpcserver.BookService{Repository: &repository.BookRepository{DB: db, QueryTimeout: queryTimeout}}
+	server := grpcserver.NewServer(bookService, authenticator, authz.DefaultPolicy(), true)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	connection, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() {
+		connection.Close()
+		server.Stop()
+		db.Close()
+	})
+	return connection
+}
+
+func TestNewServer_GivenHealthCheck_ThenReportBookServiceServing(t *testing.T) {
+	connection := startServer(t, 0)
+
+	response, err := healthpb.NewHealthClient(connection).Check(context.Background(),
+		&healthpb.HealthCheckRequest{Service: "library.v1.BookService"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+}
+
+func TestNewServer_GivenReflectionRequest_ThenListBookService(t *testing.T) {
+	connection := startServer(t, 0)
+
+	stream, err := reflectionpb.NewServerReflectionClient(connection).ServerReflectionInfo(context.Background())
+	assert.NoError(t, err)
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	assert.NoError(t, err)
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+
+	services := []string{}
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, libraryv1.BookService_ServiceDesc.ServiceName)
+}
@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRequestInProgress   = errors.New("request with this idempotency key is in progress")
+	ErrFingerprintMismatch = errors.New("idempotency key reused with a different request")
+)
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Store remembers idempotency keys together with the fingerprint of the
+// request that first used them and, once finished, the response it produced.
+type Store interface {
+	// Reserve claims key for a new request. It returns the stored response when
+	// the key already completed with the same fingerprint.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Response, error)
+	Complete(ctx context.Context, key string, response Response) error
+	Release(ctx context.Context, key string) error
+}
+
+type record struct {
+	fingerprint string
+	response    *Response
+	expiresAt   time.Time
+}
+
+// sweepInterval is how often Reserve drops the expired records of other keys;
+// the key being reserved is always checked for expiry itself.
+const sweepInterval = time.Minute
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*record
+	nextSweep time.Time
+	Now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]*record{}, Now: time.Now}
+}
+
+func (store *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Response, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.Now()
+	if now.After(store.nextSweep) {
+		for storedKey, storedRecord := range store.records {
+			if now.After(storedRecord.expiresAt) {
+				delete(store.records, storedKey)
+			}
+		}
+		store.nextSweep = now.Add(sweepInterval)
+	}
+
+	existing, exists := store.records[key]
+	switch {
+	case !exists || now.After(existing.expiresAt):
+		store.records[key] = &record{fingerprint: fingerprint, expiresAt: now.Add(ttl)}
+		return nil, nil
+	case existing.fingerprint != fingerprint:
+		return nil, ErrFingerprintMismatch
+	case existing.response == nil:
+		return nil, ErrRequestInProgress
+	}
+	return existing.response, nil
+}
+
+func (store *MemoryStore) Complete(ctx context.Context, key string, response Response) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if existing, exists := store.records[key]; exists {
+		existing.response = &response
+	}
+	return nil
+}
+
+func (store *MemoryStore) Release(ctx context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.records, key)
+	return nil
+}
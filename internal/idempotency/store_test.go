@@ -0,0 +1,68 @@
+package idempotency_test
+
+import (
+	"context"
+	"gojek/library-service-api/internal/idempotency"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserve_GivenCompletedKey_ThenReturnStoredResponse(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+
+	response, err := store.Reserve(context.Background(), "key", "fingerprint", time.Hour)
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	store.Complete(context.Background(), "key", idempotency.Response{StatusCode: 200, Body: []byte(`{"id":1}`)})
+	response, err = store.Reserve(context.Background(), "key", "fingerprint", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"id":1}`), response.Body)
+}
+
+func TestReserve_GivenKeyInProgress_ThenReturnRequestInProgressError(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+
+	store.Reserve(context.Background(), "key", "fingerprint", time.Hour)
+	_, err := store.Reserve(context.Background(), "key", "fingerprint", time.Hour)
+
+	assert.ErrorIs(t, err, idempotency.ErrRequestInProgress)
+}
+
+func TestReserve_GivenDifferentFingerprint_ThenReturnFingerprintMismatchError(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+
+	store.Reserve(context.Background(), "key", "fingerprint", time.Hour)
+	_, err := store.Reserve(context.Background(), "key", "another-fingerprint", time.Hour)
+
+	assert.ErrorIs(t, err, idempotency.ErrFingerprintMismatch)
+}
+
+func TestReserve_GivenExpiredKey_ThenReserveAgain(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := idempotency.NewMemoryStore()
+	store.Now = func() time.Time { return now }
+
+	store.Reserve(context.Background(), "key", "fingerprint", time.Hour)
+	store.Complete(context.Background(), "key", idempotency.Response{StatusCode: 200})
+	now = now.Add(2 * time.Hour)
+	response, err := store.Reserve(context.Background(), "key", "another-fingerprint", time.Hour)
+
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
+
+func TestReserve_GivenKeyExpiredBeforeNextSweep_ThenReserveAgain(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := idempotency.NewMemoryStore()
+	store.Now = func() time.Time { return now }
+
+	store.Reserve(context.Background(), "key", "fingerprint", time.Second)
+	now = now.Add(2 * time.Second)
+	response, err := store.Reserve(context.Background(), "key", "another-fingerprint", time.Second)
+
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
@@ -0,0 +1,37 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"gojek/library-service-api/internal/logger"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_GivenContextWithRequestID_ThenLogLineContainsRequestID(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	log := logger.New(buffer, "info", "json")
+
+	ctx := logger.WithRequestID(context.Background(), "request-123")
+	log.InfoContext(ctx, "book saved", "book_id", 1)
+
+	line := map[string]interface{}{}
+	err := json.Unmarshal(buffer.Bytes(), &line)
+	assert.NoError(t, err)
+	assert.Equal(t, "request-123", line["request_id"])
+	assert.Equal(t, "book saved", line["msg"])
+}
+
+func TestNew_GivenWarnLevel_ThenInfoLogIsDiscarded(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	log := logger.New(buffer, "warn", "text")
+
+	log.Info("book saved")
+	log.Warn("book not found")
+
+	assert.NotContains(t, buffer.String(), "book saved")
+	assert.True(t, strings.Contains(buffer.String(), "book not found"))
+}
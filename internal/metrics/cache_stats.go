@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"gojek/library-service-api/internal/cache"
+	"io"
+)
+
+type CacheStatsCollector struct {
+	Name  string
+	Cache interface{ Stats() cache.Stats }
+}
+
+func (collector *CacheStatsCollector) WriteText(w io.Writer) error {
+	stats := collector.Cache.Stats()
+	series := []struct {
+		name       string
+		help       string
+		metricType string
+		value      float64
+	}{
+		{"cache_hits_total", "Total number of lookups served from the cache.", "counter", float64(stats.Hits)},
+		{"cache_misses_total", "Total number of lookups the cache could not serve.", "counter", float64(stats.Misses)},
+		{"cache_evictions_total", "Total number of entries evicted to make room.", "counter", float64(stats.Evictions)},
+		{"cache_entries", "Number of entries currently cached.", "gauge", float64(stats.Entries)},
+	}
+	for _, sample := range series {
+		writeHeader(w, sample.name, sample.help, sample.metricType)
+		writeSample(w, sample.name, []string{"cache"}, []string{collector.Name}, "", "", sample.value)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package metrics_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/metrics"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheStatsCollector_GivenCache_ThenWriteHitsAndMisses(t *testing.T) {
+	lru := cache.NewLRU[int, string](1, 0)
+	lru.Add(1, "Clean Code")
+	lru.Get(1)
+	lru.Get(2)
+
+	buffer := &bytes.Buffer{}
+	err := (&metrics.CacheStatsCollector{Name: "books", Cache: lru}).WriteText(buffer)
+
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), "# TYPE cache_hits_total counter\ncache_hits_total{cache=\"books\"} 1\n")
+	assert.Contains(t, buffer.String(), "cache_misses_total{cache=\"books\"} 1\n")
+	assert.Contains(t, buffer.String(), "# TYPE cache_entries gauge\ncache_entries{cache=\"books\"} 1\n")
+}
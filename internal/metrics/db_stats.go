@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+)
+
+type DBStatsCollector struct {
+	DB *sql.DB
+}
+
+func (collector *DBStatsCollector) WriteText(w io.Writer) error {
+	stats := collector.DB.Stats()
+	gauges := []struct {
+		name       string
+		help       string
+		metricType string
+		value      float64
+	}{
+		{"db_max_open_connections", "Maximum number of open connections to the database.", "gauge", float64(stats.MaxOpenConnections)},
+		{"db_open_connections", "Number of established connections, both in use and idle.", "gauge", float64(stats.OpenConnections)},
+		{"db_in_use_connections", "Number of connections currently in use.", "gauge", float64(stats.InUse)},
+		{"db_idle_connections", "Number of idle connections.", "gauge", float64(stats.Idle)},
+		{"db_wait_count_total", "Total number of connections waited for.", "counter", float64(stats.WaitCount)},
+		{"db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", "counter", stats.WaitDuration.Seconds()},
+		{"db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", "counter", float64(stats.MaxIdleClosed)},
+		{"db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", "counter", float64(stats.MaxLifetimeClosed)},
+	}
+	for _, gauge := range gauges {
+		writeHeader(w, gauge.name, gauge.help, gauge.metricType)
+		writeSample(w, gauge.name, nil, nil, "", "", gauge.value)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package metrics_test
+
+import (
+	"bytes"
+	"database/sql"
+	"gojek/library-service-api/internal/metrics"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBStatsCollector_GivenDB_ThenWritePoolGauges(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+
+	buffer := &bytes.Buffer{}
+	err := (&metrics.DBStatsCollector{DB: db}).WriteText(buffer)
+
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), "# TYPE db_max_open_connections gauge\ndb_max_open_connections 7\n")
+	assert.Contains(t, buffer.String(), "db_in_use_connections 0\n")
+	assert.Contains(t, buffer.String(), "# TYPE db_wait_count_total counter\n")
+}
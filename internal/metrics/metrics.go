@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector is anything that can write itself in the Prometheus text
+// exposition format.
+type Collector interface {
+	WriteText(w io.Writer) error
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (registry *Registry) MustRegister(collectors ...Collector) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.collectors = append(registry.collectors, collectors...)
+}
+
+func (registry *Registry) WriteText(w io.Writer) error {
+	registry.mu.Lock()
+	collectors := append([]Collector{}, registry.collectors...)
+	registry.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, collector := range collectors {
+		if err := collector.WriteText(buffered); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		registry.WriteText(w)
+	})
+}
+
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.Mutex
+	counters   map[string]*Counter
+}
+
+type Counter struct {
+	labelValues []string
+	mu          sync.Mutex
+	value       float64
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{name: name, help: help, labelNames: labelNames, counters: map[string]*Counter{}}
+}
+
+func (counterVec *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	key := seriesKey(labelValues)
+	counterVec.mu.Lock()
+	defer counterVec.mu.Unlock()
+	counter, exists := counterVec.counters[key]
+	if !exists {
+		counter = &Counter{labelValues: labelValues}
+		counterVec.counters[key] = counter
+	}
+	return counter
+}
+
+func (counter *Counter) Inc() {
+	counter.Add(1)
+}
+
+func (counter *Counter) Add(value float64) {
+	counter.mu.Lock()
+	counter.value += value
+	counter.mu.Unlock()
+}
+
+func (counterVec *CounterVec) WriteText(w io.Writer) error {
+	writeHeader(w, counterVec.name, counterVec.help, "counter")
+	counterVec.mu.Lock()
+	defer counterVec.mu.Unlock()
+	for _, key := range sortedKeys(counterVec.counters) {
+		counter := counterVec.counters[key]
+		counter.mu.Lock()
+		value := counter.value
+		counter.mu.Unlock()
+		writeSample(w, counterVec.name, counterVec.labelNames, counter.labelValues, "", "", value)
+	}
+	return nil
+}
+
+type HistogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+	mu         sync.Mutex
+	histograms map[string]*Histogram
+}
+
+type Histogram struct {
+	labelValues  []string
+	buckets      []float64
+	mu           sync.Mutex
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{name: name, help: help, labelNames: labelNames, buckets: buckets, histograms: map[string]*Histogram{}}
+}
+
+func (histogramVec *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	key := seriesKey(labelValues)
+	histogramVec.mu.Lock()
+	defer histogramVec.mu.Unlock()
+	histogram, exists := histogramVec.histograms[key]
+	if !exists {
+		histogram = &Histogram{labelValues: labelValues, buckets: histogramVec.buckets, bucketCounts: make([]uint64, len(histogramVec.buckets))}
+		histogramVec.histograms[key] = histogram
+	}
+	return histogram
+}
+
+func (histogram *Histogram) Observe(value float64) {
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+	for i, upperBound := range histogram.buckets {
+		if value <= upperBound {
+			histogram.bucketCounts[i]++
+		}
+	}
+	histogram.sum += value
+	histogram.count++
+}
+
+func (histogramVec *HistogramVec) WriteText(w io.Writer) error {
+	writeHeader(w, histogramVec.name, histogramVec.help, "histogram")
+	histogramVec.mu.Lock()
+	defer histogramVec.mu.Unlock()
+	for _, key := range sortedKeys(histogramVec.histograms) {
+		histogram := histogramVec.histograms[key]
+		histogram.mu.Lock()
+		for i, upperBound := range histogram.buckets {
+			writeSample(w, histogramVec.name+"_bucket", histogramVec.labelNames, histogram.labelValues, "le", formatValue(upperBound), float64(histogram.bucketCounts[i]))
+		}
+		writeSample(w, histogramVec.name+"_bucket", histogramVec.labelNames, histogram.labelValues, "le", "+Inf", float64(histogram.count))
+		writeSample(w, histogramVec.name+"_sum", histogramVec.labelNames, histogram.labelValues, "", "", histogram.sum)
+		writeSample(w, histogramVec.name+"_count", histogramVec.labelNames, histogram.labelValues, "", "", float64(histogram.count))
+		histogram.mu.Unlock()
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help), name, metricType)
+}
+
+func writeSample(w io.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	pairs := []string{}
+	for i, labelName := range labelNames {
+		pairs = append(pairs, labelName+`="`+escapeLabelValue(labelValues[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	if len(pairs) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatValue(value))
+		return
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatValue(value))
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func sortedKeys[V any](series map[string]V) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
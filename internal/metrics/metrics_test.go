@@ -0,0 +1,78 @@
+package metrics_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText_GivenCounterVec_ThenWriteSortedSamples(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := metrics.NewCounterVec("http_requests_total", "Total number of HTTP requests.", "route", "status")
+	registry.MustRegister(requests)
+
+	requests.WithLabelValues("/ping", "200").Inc()
+	requests.WithLabelValues("/books", "500").Add(2)
+
+	buffer := &bytes.Buffer{}
+	err := registry.WriteText(buffer)
+
+	expectedText := `# HELP http_requests_total Total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{route="/books",status="500"} 2
+http_requests_total{route="/ping",status="200"} 1
+`
+	assert.NoError(t, err)
+	assert.Equal(t, expectedText, buffer.String())
+}
+
+func TestWriteText_GivenHistogramVec_ThenWriteCumulativeBuckets(t *testing.T) {
+	registry := metrics.NewRegistry()
+	durations := metrics.NewHistogramVec("db_query_duration_seconds", "Query duration.", []float64{0.1, 1}, "method")
+	registry.MustRegister(durations)
+
+	durations.WithLabelValues("FindAllBooks").Observe(0.05)
+	durations.WithLabelValues("FindAllBooks").Observe(0.5)
+	durations.WithLabelValues("FindAllBooks").Observe(2)
+
+	buffer := &bytes.Buffer{}
+	registry.WriteText(buffer)
+
+	expectedText := `# HELP db_query_duration_seconds Query duration.
+# TYPE db_query_duration_seconds histogram
+db_query_duration_seconds_bucket{method="FindAllBooks",le="0.1"} 1
+db_query_duration_seconds_bucket{method="FindAllBooks",le="1"} 2
+db_query_duration_seconds_bucket{method="FindAllBooks",le="+Inf"} 3
+db_query_duration_seconds_sum{method="FindAllBooks"} 2.55
+db_query_duration_seconds_count{method="FindAllBooks"} 3
+`
+	assert.Equal(t, expectedText, buffer.String())
+}
+
+func TestWriteText_GivenLabelValueWithQuotes_ThenEscapeLabelValue(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := metrics.NewCounterVec("requests_total", "Requests.", "path")
+	registry.MustRegister(requests)
+
+	requests.WithLabelValues("say \"hi\"\n").Inc()
+
+	buffer := &bytes.Buffer{}
+	registry.WriteText(buffer)
+
+	assert.Contains(t, buffer.String(), `requests_total{path="say \"hi\"\n"} 1`)
+}
+
+func TestHandler_GivenRequest_ThenReturnTextExpositionContentType(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, metrics.ContentType, w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := newResponseRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		slog.InfoContext(r.Context(), "http request",
+			"method", r.Method,
+			"route", RouteFromContext(r.Context()),
+			"path", r.URL.Path,
+			"status", recorder.Status(),
+			"bytes", recorder.bytesWritten,
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+		)
+	})
+}
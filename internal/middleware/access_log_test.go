@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"gojek/library-service-api/internal/logger"
+	"gojek/library-service-api/internal/middleware"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog_GivenRequest_ThenLogMethodRouteStatusAndBytes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	defaultLogger := slog.Default()
+	slog.SetDefault(logger.New(buffer, "info", "json"))
+	defer slog.SetDefault(defaultLogger)
+
+	handler := middleware.AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/books", nil)
+	req = req.WithContext(middleware.WithRoute(req.Context(), "/books"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := map[string]interface{}{}
+	err := json.Unmarshal(buffer.Bytes(), &line)
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", line["method"])
+	assert.Equal(t, "/books", line["route"])
+	assert.Equal(t, float64(http.StatusCreated), line["status"])
+	assert.Equal(t, float64(len("created")), line["bytes"])
+	assert.Contains(t, line, "latency_ms")
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"gojek/library-service-api/internal/auth"
+	"log/slog"
+	"net/http"
+)
+
+// Authenticate puts the caller's principal on the request context. Requests
+// without credentials are rejected unless they are reads and publicReads is set.
+func Authenticate(authenticator *auth.Authenticator, publicReads bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticator.Authenticate(r)
+			switch {
+			case errors.Is(err, auth.ErrMissingCredentials) && publicReads && isReadRequest(r):
+				next.ServeHTTP(w, r)
+				return
+			case errors.Is(err, auth.ErrMissingCredentials), errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrInvalidAPIKey):
+				slog.WarnContext(r.Context(), "authentication failed", "error", err, "method", r.Method, "path", r.URL.Path)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="library-service-api"`)
+				writeProblem(w, r, http.StatusUnauthorized, "Valid credentials are required.", nil)
+				return
+			case err != nil:
+				slog.ErrorContext(r.Context(), "failed to authenticate", "error", err, "method", r.Method, "path", r.URL.Path)
+				writeProblem(w, r, http.StatusInternalServerError, "Unable to verify credentials.", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+type anonymousAllowedKey struct{}
+
+// AllowAnonymous lets Authenticate pass callers without credentials through
+// whatever the method when public reads are on. It is for handlers that
+// authorize each operation themselves, such as /graphql, whose queries arrive
+// by POST.
+func AllowAnonymous(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), anonymousAllowedKey{}, true)))
+	})
+}
+
+func isReadRequest(r *http.Request) bool {
+	allowed, _ := r.Context().Value(anonymousAllowedKey{}).(bool)
+	return allowed || isReadMethod(r.Method)
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
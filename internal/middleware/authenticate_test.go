@@ -0,0 +1,101 @@
+package middleware_test
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAPIKeyFinder struct {
+	apiKeys map[string]domain.APIKey
+}
+
+func (finder *fakeAPIKeyFinder) FindAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	apiKey, exists := finder.apiKeys[keyHash]
+	if !exists {
+		return domain.APIKey{}, sql.ErrNoRows
+	}
+	return apiKey, nil
+}
+
+func newAuthenticateHandler(publicReads bool, principal *auth.Principal) http.Handler {
+	authenticator := &auth.Authenticator{APIKeys: &fakeAPIKeyFinder{apiKeys: map[string]domain.APIKey{
+		auth.HashAPIKey("secret-key"): {Subject: "catalogue-importer", Roles: []string{"librarian"}},
+	}}}
+	return middleware.Authenticate(authenticator, publicReads)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*principal, _ = auth.PrincipalFromContext(r.Context())
+	}))
+}
+
+func TestAuthenticate_GivenAnonymousWriteRequest_ThenReturnUnauthorized(t *testing.T) {
+	principal := auth.Principal{}
+	handler := newAuthenticateHandler(true, &principal)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/books", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestAuthenticate_GivenAnonymousReadRequestAndPublicReads_ThenCallNextHandler(t *testing.T) {
+	principal := auth.Principal{}
+	handler := newAuthenticateHandler(true, &principal)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, principal.Subject)
+}
+
+func TestAuthenticate_GivenAnonymousReadRequestAndPrivateReads_ThenReturnUnauthorized(t *testing.T) {
+	principal := auth.Principal{}
+	handler := newAuthenticateHandler(false, &principal)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticate_GivenValidAPIKey_ThenPutPrincipalOnContext(t *testing.T) {
+	principal := auth.Principal{}
+	handler := newAuthenticateHandler(true, &principal)
+
+	req := httptest.NewRequest(http.MethodDelete, "/books/1", nil)
+	req.Header.Set(auth.APIKeyHeader, "secret-key")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "catalogue-importer", principal.Subject)
+}
+
+func TestAuthenticate_GivenAnonymousPostAllowedAndPublicReads_ThenCallNextHandler(t *testing.T) {
+	principal := auth.Principal{}
+	handler := middleware.AllowAnonymous(newAuthenticateHandler(true, &principal))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, principal.Subject)
+}
+
+func TestAuthenticate_GivenAnonymousPostAllowedAndPrivateReads_ThenReturnUnauthorized(t *testing.T) {
+	principal := auth.Principal{}
+	handler := middleware.AllowAnonymous(newAuthenticateHandler(false, &principal))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
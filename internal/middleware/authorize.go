@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"log/slog"
+	"net/http"
+)
+
+// Authorize checks the permission required for the request method against the
+// roles of the authenticated principal. Methods without a required permission
+// are passed through.
+func Authorize(policy *authz.Policy, permissionsByMethod map[string]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			permission, required := permissionsByMethod[r.Method]
+			if !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			roles, subject := []string{authz.AnonymousRole}, ""
+			if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+				roles, subject = principal.Roles, principal.Subject
+			}
+			if !policy.Allows(roles, permission) {
+				slog.WarnContext(r.Context(), "authorization denied",
+					"subject", subject, "roles", roles, "permission", permission, "method", r.Method, "path", r.URL.Path)
+				writeProblem(w, r, http.StatusForbidden, "Missing permission: "+permission+".",
+					map[string]interface{}{"missingPermission": permission})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
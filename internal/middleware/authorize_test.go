@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/authz"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthorizeHandler() http.Handler {
+	return middleware.Authorize(authz.DefaultPolicy(), map[string]string{
+		http.MethodGet:    authz.PermissionReadBooks,
+		http.MethodDelete: authz.PermissionDeleteBooks,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+func TestAuthorize_GivenLibrarianDeletingBook_ThenReturnForbiddenWithMissingPermission(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/books/1", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: "librarian", Roles: []string{"librarian"}}))
+	w := httptest.NewRecorder()
+	newAuthorizeHandler().ServeHTTP(w, req)
+
+	response := map[string]interface{}{}
+	json.NewDecoder(w.Body).Decode(&response)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, authz.PermissionDeleteBooks, response["missingPermission"])
+	assert.Equal(t, "Missing permission: books:delete.", response["detail"])
+}
+
+func TestAuthorize_GivenAdminDeletingBook_ThenCallNextHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/books/1", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Subject: "admin", Roles: []string{"admin"}}))
+	w := httptest.NewRecorder()
+	newAuthorizeHandler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorize_GivenAnonymousRead_ThenUseAnonymousRole(t *testing.T) {
+	w := httptest.NewRecorder()
+	newAuthorizeHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books/1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit_GivenDeclaredLengthOverLimit_ThenReturnRequestEntityTooLarge(t *testing.T) {
+	called := false
+	handler := middleware.BodyLimit(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader("too large"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestBodyLimit_GivenStreamedBodyOverLimit_ThenReadFails(t *testing.T) {
+	var readErr error
+	handler := middleware.BodyLimit(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/books", io.NopCloser(strings.NewReader("too large")))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	maxBytesErr := &http.MaxBytesError{}
+	assert.ErrorAs(t, readErr, &maxBytesErr)
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+type resettableWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressionEncoders lists the supported encodings in the order preferred
+// when the client weighs them equally.
+var compressionEncoders = []struct {
+	Name string
+	Pool *sync.Pool
+}{
+	{Name: "zstd", Pool: &sync.Pool{New: func() interface{} {
+		encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return encoder
+	}}},
+	{Name: "br", Pool: &sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	}}},
+	{Name: "gzip", Pool: &sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}}},
+}
+
+// Compress encodes response bodies with the best of zstd, br and gzip that
+// Accept-Encoding allows. Bodies are held back until minSize bytes have been
+// written; shorter bodies are sent as they are, since compressing them costs
+// more than it saves. A flush before that point also sends the body as it is.
+func Compress(minSize int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding, pool := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if pool == nil || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			writer := &compressWriter{ResponseWriter: w, encoding: encoding, pool: pool, minSize: minSize}
+			next.ServeHTTP(writer, r)
+			writer.close()
+		})
+	}
+}
+
+func negotiateEncoding(acceptEncoding string) (string, *sync.Pool) {
+	qualities := map[string]float64{}
+	for _, element := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(element, ";")
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = quality
+	}
+
+	bestName, bestPool, bestQuality := "", (*sync.Pool)(nil), 0.0
+	for _, encoder := range compressionEncoders {
+		quality, exists := qualities[encoder.Name]
+		if !exists {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			bestName, bestPool, bestQuality = encoder.Name, encoder.Pool, quality
+		}
+	}
+	return bestName, bestPool
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding   string
+	pool       *sync.Pool
+	minSize    int
+	statusCode int
+	buffer     []byte
+	decided    bool
+	encoder    resettableWriter
+}
+
+func (writer *compressWriter) WriteHeader(statusCode int) {
+	if writer.decided {
+		writer.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	if statusCode < http.StatusOK {
+		writer.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	if writer.statusCode == 0 {
+		writer.statusCode = statusCode
+	}
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified {
+		writer.decide(false)
+	}
+}
+
+func (writer *compressWriter) Write(data []byte) (int, error) {
+	if !writer.decided {
+		writer.buffer = append(writer.buffer, data...)
+		if len(writer.buffer) >= writer.minSize {
+			if err := writer.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(data), nil
+	}
+	if writer.encoder != nil {
+		return writer.encoder.Write(data)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *compressWriter) Flush() {
+	if !writer.decided {
+		writer.decide(false)
+	}
+	if writer.encoder != nil {
+		writer.encoder.Flush()
+	}
+	http.NewResponseController(writer.ResponseWriter).Flush()
+}
+
+func (writer *compressWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
+
+// decide sends the status and the held-back bytes, compressed when compress
+// is set and the handler has not encoded the body itself.
+func (writer *compressWriter) decide(compress bool) error {
+	writer.decided = true
+	header := writer.ResponseWriter.Header()
+	if compress && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", writer.encoding)
+		header.Del("Content-Length")
+		writer.encoder = writer.pool.Get().(resettableWriter)
+		writer.encoder.Reset(writer.ResponseWriter)
+	}
+	if writer.statusCode == 0 {
+		writer.statusCode = http.StatusOK
+	}
+	writer.ResponseWriter.WriteHeader(writer.statusCode)
+
+	buffered := writer.buffer
+	writer.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	_, err := writer.Write(buffered)
+	return err
+}
+
+// close is skipped when the handler panics, so that an aborted response is
+// not finished off as a complete compressed stream.
+func (writer *compressWriter) close() {
+	if !writer.decided {
+		writer.decide(false)
+	}
+	if writer.encoder != nil {
+		writer.encoder.Close()
+		writer.pool.Put(writer.encoder)
+	}
+}
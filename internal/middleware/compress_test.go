@@ -0,0 +1,129 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"gojek/library-service-api/internal/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressHandler(minSize int, body string) http.Handler {
+	return middleware.Compress(minSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, body[:len(body)/2])
+		io.WriteString(w, body[len(body)/2:])
+	}))
+}
+
+func decompress(t *testing.T, encoding string, data []byte) string {
+	t.Helper()
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		assert.NoError(t, err)
+		reader = gzipReader
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(data))
+	case "zstd":
+		zstdReader, err := zstd.NewReader(bytes.NewReader(data))
+		assert.NoError(t, err)
+		defer zstdReader.Close()
+		decoded, err := io.ReadAll(zstdReader)
+		assert.NoError(t, err)
+		return string(decoded)
+	}
+	decoded, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(decoded)
+}
+
+func TestCompress_GivenAcceptedEncodings_ThenCompressBodyOverMinSize(t *testing.T) {
+	body := strings.Repeat(`{"title":"Clean Code"},`, 100)
+	cases := map[string]string{
+		"gzip":                    "gzip",
+		"br":                      "br",
+		"zstd":                    "zstd",
+		"gzip, deflate, br, zstd": "zstd",
+		"gzip;q=1.0, br;q=0.5":    "gzip",
+		"*":                       "zstd",
+		"*, zstd;q=0, br;q=0":     "gzip",
+	}
+	for acceptEncoding, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+		compressHandler(1024, body).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code, acceptEncoding)
+		assert.Equal(t, expected, w.Header().Get("Content-Encoding"), acceptEncoding)
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"), acceptEncoding)
+		assert.Less(t, w.Body.Len(), len(body), acceptEncoding)
+		assert.Equal(t, body, decompress(t, expected, w.Body.Bytes()), acceptEncoding)
+	}
+}
+
+func TestCompress_GivenBodyUnderMinSize_ThenSendUncompressed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	compressHandler(1024, `{"title":"Clean Code"}`).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"title":"Clean Code"}`, w.Body.String())
+}
+
+func TestCompress_GivenNoAcceptableEncoding_ThenSendUncompressed(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+	for _, acceptEncoding := range []string{"", "identity", "deflate", "gzip;q=0"} {
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+		compressHandler(1024, body).ServeHTTP(w, req)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"), acceptEncoding)
+		assert.Equal(t, body, w.Body.String(), acceptEncoding)
+	}
+}
+
+func TestCompress_GivenFlushBeforeMinSize_ThenSendUncompressed(t *testing.T) {
+	handler := middleware.Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "data: ping\n\n")
+		http.NewResponseController(w).Flush()
+		io.WriteString(w, strings.Repeat("a", 2048))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.True(t, w.Flushed)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: ping\n\n"+strings.Repeat("a", 2048), w.Body.String())
+}
+
+func TestCompress_GivenAlreadyEncodedBody_ThenLeaveBodyAlone(t *testing.T) {
+	handler := middleware.Compress(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("already gzipped"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "already gzipped", w.Body.String())
+}
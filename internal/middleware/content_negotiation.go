@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/codec"
+	"net/http"
+	"strings"
+)
+
+// ContentNegotiation answers 406 when the Accept header allows none of the
+// supported media types and 415 when a POST or PUT body is in an unsupported
+// one, before the handler has had a chance to change anything.
+func ContentNegotiation() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := codec.Negotiate(r.Header.Get("Accept")); err != nil {
+				w.Header().Add("Vary", "Accept")
+				writeProblem(w, r, http.StatusNotAcceptable, "Supported media types are "+supportedMediaTypes()+".", nil)
+				return
+			}
+			if r.Method == http.MethodPost || r.Method == http.MethodPut {
+				if _, err := codec.ForContentType(r.Header.Get("Content-Type")); err != nil {
+					writeProblem(w, r, http.StatusUnsupportedMediaType, "Supported media types are "+supportedMediaTypes()+".", nil)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func supportedMediaTypes() string {
+	mediaTypes := make([]string, 0, len(codec.Codecs))
+	for _, supported := range codec.Codecs {
+		mediaTypes = append(mediaTypes, supported.MediaTypes()[0])
+	}
+	return strings.Join(mediaTypes, ", ")
+}
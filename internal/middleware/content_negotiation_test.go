@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentNegotiation_GivenUnsupportedAccept_ThenReturnNotAcceptable(t *testing.T) {
+	called := false
+	handler := middleware.ContentNegotiation()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Accept", "image/png")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "text/csv")
+}
+
+func TestContentNegotiation_GivenUnsupportedContentType_ThenReturnUnsupportedMediaType(t *testing.T) {
+	called := false
+	handler := middleware.ContentNegotiation()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader("title=Dune"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestContentNegotiation_GivenSupportedTypes_ThenCallNext(t *testing.T) {
+	called := false
+	handler := middleware.ContentNegotiation()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodPut, "/books/1", strings.NewReader("<book><title>Dune</title></book>"))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "text/csv, application/json;q=0.5")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.True(t, called)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/config"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CORS answers preflight requests itself and adds the CORS response headers
+// for allowed origins. Requests from other origins pass through unchanged, so
+// the browser enforces the policy.
+func CORS(corsConfig config.CORSConfig) Middleware {
+	allowAnyOrigin := slices.Contains(corsConfig.AllowedOrigins, "*") && !corsConfig.AllowCredentials
+	allowedMethods := strings.Join(corsConfig.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(corsConfig.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(corsConfig.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(corsConfig.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			if !allowAnyOrigin && !slices.Contains(corsConfig.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAnyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if corsConfig.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/config"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSHandler(corsConfig config.CORSConfig, called *bool) http.Handler {
+	return middleware.CORS(corsConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+	}))
+}
+
+func dashboardCORSConfig() config.CORSConfig {
+	return config.CORSConfig{
+		AllowedOrigins:   []string{"https://dashboard.library.test"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+}
+
+func TestCORS_GivenPreflightFromAllowedOrigin_ThenReturnNoContentWithPreflightHeaders(t *testing.T) {
+	called := false
+	handler := newCORSHandler(dashboardCORSConfig(), &called)
+
+	req := httptest.NewRequest(http.MethodOptions, "/books", nil)
+	req.Header.Set("Origin", "https://dashboard.library.test")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://dashboard.library.test", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORS_GivenRequestFromAllowedOrigin_ThenExposeHeaders(t *testing.T) {
+	called := false
+	handler := newCORSHandler(dashboardCORSConfig(), &called)
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Origin", "https://dashboard.library.test")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.True(t, called)
+	assert.Equal(t, "https://dashboard.library.test", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestCORS_GivenRequestFromUnknownOrigin_ThenOmitCORSHeaders(t *testing.T) {
+	called := false
+	handler := newCORSHandler(dashboardCORSConfig(), &called)
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Origin", "https://evil.test")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.True(t, called)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
+func TestCORS_GivenWildcardOriginWithoutCredentials_ThenAllowAnyOrigin(t *testing.T) {
+	called := false
+	handler := newCORSHandler(config.CORSConfig{AllowedOrigins: []string{"*"}}, &called)
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("Origin", "https://partner.test")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+}
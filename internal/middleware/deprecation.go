@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deprecation marks responses of a superseded API version with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and links to the same
+// resource under successorPrefix in place of prefix. Zero times are omitted.
+func Deprecation(deprecatedAt, sunsetAt time.Time, prefix, successorPrefix string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !deprecatedAt.IsZero() {
+				w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+			}
+			if !sunsetAt.IsZero() {
+				w.Header().Set("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+			}
+			successor := successorPrefix + strings.TrimPrefix(r.URL.Path, prefix)
+			w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
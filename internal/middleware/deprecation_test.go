@@ -0,0 +1,35 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation_GivenVersionedPath_ThenSetDeprecationSunsetAndSuccessorLink(t *testing.T) {
+	deprecatedAt := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+	handler := middleware.Deprecation(deprecatedAt, sunsetAt, "/v1", "/v2")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/books/3", nil))
+
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/books/3>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestDeprecation_GivenUnversionedPathAndNoDates_ThenOnlyLinkSuccessor(t *testing.T) {
+	handler := middleware.Deprecation(time.Time{}, time.Time{}, "", "/v2")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/books>; rel="successor-version"`, w.Header().Get("Link"))
+}
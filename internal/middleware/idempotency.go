@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/idempotency"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Idempotency replays the stored response for POST requests that repeat an
+// Idempotency-Key within ttl. Keys are scoped to the caller and route.
+func Idempotency(store idempotency.Store, ttl time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idempotencyKey) > 255 {
+				writeProblem(w, r, http.StatusBadRequest, "Idempotency-Key must not exceed 255 characters.", nil)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeProblem(w, r, http.StatusBadRequest, "Unable to read request body.", nil)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			subject := ""
+			if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+				subject = principal.Subject
+			}
+			key := subject + "|" + RouteFromContext(r.Context()) + "|" + idempotencyKey
+			fingerprint := sha256.Sum256(append([]byte(r.Method+" "+r.URL.RequestURI()+"\n"+r.Header.Get("Content-Type")+"\n"), body...))
+
+			storedResponse, err := store.Reserve(r.Context(), key, hex.EncodeToString(fingerprint[:]), ttl)
+			switch {
+			case errors.Is(err, idempotency.ErrFingerprintMismatch):
+				writeProblem(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request.", nil)
+				return
+			case errors.Is(err, idempotency.ErrRequestInProgress):
+				writeProblem(w, r, http.StatusConflict, "A request with this Idempotency-Key is still in progress.", nil)
+				return
+			case err != nil:
+				slog.ErrorContext(r.Context(), "failed to reserve idempotency key", "error", err)
+				writeProblem(w, r, http.StatusInternalServerError, "Unable to process Idempotency-Key.", nil)
+				return
+			case storedResponse != nil:
+				for name, values := range storedResponse.Header {
+					for _, value := range values {
+						if !slices.Contains(w.Header().Values(name), value) {
+							w.Header().Add(name, value)
+						}
+					}
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(storedResponse.StatusCode)
+				w.Write(storedResponse.Body)
+				return
+			}
+
+			headerBefore := w.Header().Clone()
+			capture := &captureWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			completed := false
+			defer func() {
+				if !completed {
+					store.Release(r.Context(), key)
+				}
+			}()
+			next.ServeHTTP(capture, r)
+
+			// Server errors are not remembered so that the client's retry gets
+			// another chance to succeed.
+			if capture.statusCode >= http.StatusInternalServerError {
+				return
+			}
+			if capture.header == nil {
+				capture.header = w.Header().Clone()
+			}
+			response := idempotency.Response{StatusCode: capture.statusCode, Header: handlerHeader(headerBefore, capture.header), Body: capture.body.Bytes()}
+			if err := store.Complete(r.Context(), key, response); err != nil {
+				slog.ErrorContext(r.Context(), "failed to store idempotent response", "error", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// handlerHeader keeps only the header values added by the handler itself, so a
+// replay does not repeat per-request headers such as X-Request-ID but still
+// carries, say, the Vary: Accept the handler added next to Compress's
+// Vary: Accept-Encoding.
+func handlerHeader(before, after http.Header) http.Header {
+	header := http.Header{}
+	for name, values := range after {
+		existing := append([]string{}, before[name]...)
+		for _, value := range values {
+			if index := slices.Index(existing, value); index >= 0 {
+				existing = slices.Delete(existing, index, index+1)
+				continue
+			}
+			header[name] = append(header[name], value)
+		}
+	}
+	return header
+}
+
+// captureWriter records the response as the handler wrote it. The header is
+// snapshotted when the handler sends it, before outer middlewares such as
+// Compress add Content-Encoding for the body they are about to encode, since
+// the body captured here is the one before encoding.
+type captureWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	header      http.Header
+	body        bytes.Buffer
+}
+
+func (writer *captureWriter) WriteHeader(statusCode int) {
+	if !writer.wroteHeader {
+		writer.statusCode, writer.wroteHeader = statusCode, true
+		writer.header = writer.ResponseWriter.Header().Clone()
+	}
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (writer *captureWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		writer.wroteHeader = true
+		writer.header = writer.ResponseWriter.Header().Clone()
+	}
+	writer.body.Write(data)
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *captureWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
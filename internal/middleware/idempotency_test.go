@@ -0,0 +1,134 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/idempotency"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newIdempotentHandler(calls *int, statusCode int) http.Handler {
+	return middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		w.Write([]byte(`{"id":` + strconv.Itoa(*calls) + `}`))
+	}))
+}
+
+func newIdempotentRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+	req.Header.Set(middleware.IdempotencyKeyHeader, "retry-1")
+	return req
+}
+
+func TestIdempotency_GivenReplayedRequest_ThenReturnOriginalResponse(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(&calls, http.StatusOK)
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest(`{"title":"Clean Code"}`))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newIdempotentRequest(`{"title":"Clean Code"}`))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, `{"id":1}`, second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_GivenReusedKeyWithDifferentBody_ThenReturnUnprocessableEntity(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(&calls, http.StatusOK)
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(`{"title":"Clean Code"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newIdempotentRequest(`{"title":"Refactoring"}`))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestIdempotency_GivenServerError_ThenAllowRetry(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(&calls, http.StatusInternalServerError)
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(`{"title":"Clean Code"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(`{"title":"Clean Code"}`))
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_GivenNoIdempotencyKey_ThenCallHandlerEveryTime(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(&calls, http.StatusOK)
+
+	for i := 0; i < 2; i++ {
+		req := newIdempotentRequest(`{"title":"Clean Code"}`)
+		req.Header.Del(middleware.IdempotencyKeyHeader)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_GivenReplayedRequest_ThenKeepVaryValuesAddedByHandler(t *testing.T) {
+	handler := middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		w.Header().Add("Vary", "Accept-Encoding")
+		handler.ServeHTTP(w, newIdempotentRequest(`{"title":"Clean Code"}`))
+		return w
+	}
+
+	serve()
+	replayed := serve()
+
+	assert.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, []string{"Accept-Encoding", "Accept"}, replayed.Header().Values("Vary"))
+}
+
+func TestIdempotency_GivenReusedKeyWithDifferentContentType_ThenReturnUnprocessableEntity(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(&calls, http.StatusOK)
+
+	first := newIdempotentRequest(`{"title":"Clean Code"}`)
+	first.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), first)
+	second := newIdempotentRequest(`{"title":"Clean Code"}`)
+	second.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, second)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestIdempotency_GivenCompressedResponse_ThenReplayDecodedBodyWithoutContentEncoding(t *testing.T) {
+	handler := middleware.Chain(middleware.Compress(10), middleware.Idempotency(idempotency.NewMemoryStore(), time.Hour))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(strings.Repeat("x", 20)))
+		}))
+
+	first := httptest.NewRecorder()
+	compressedReq := newIdempotentRequest(`{"title":"Clean Code"}`)
+	compressedReq.Header.Set("Accept-Encoding", "gzip")
+	handler.ServeHTTP(first, compressedReq)
+	replayed := httptest.NewRecorder()
+	handler.ServeHTTP(replayed, newIdempotentRequest(`{"title":"Clean Code"}`))
+
+	assert.Equal(t, "gzip", first.Header().Get("Content-Encoding"))
+	assert.Equal(t, "true", replayed.Header().Get("Idempotent-Replayed"))
+	assert.Empty(t, replayed.Header().Get("Content-Encoding"))
+	assert.Equal(t, strings.Repeat("x", 20), replayed.Body.String())
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func Metrics(registry *metrics.Registry) Middleware {
+	requests := metrics.NewCounterVec("http_requests_total",
+		"Total number of HTTP requests.", "method", "route", "status")
+	durations := metrics.NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency in seconds.", metrics.DefaultBuckets, "method", "route", "status")
+	registry.MustRegister(requests, durations)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := newResponseRecorder(w)
+			next.ServeHTTP(recorder, r)
+
+			route, status := RouteFromContext(r.Context()), strconv.Itoa(recorder.Status())
+			requests.WithLabelValues(r.Method, route, status).Inc()
+			durations.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middleware_test
+
+import (
+	"bytes"
+	"gojek/library-service-api/internal/metrics"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_GivenRequest_ThenCountRequestByRouteAndStatus(t *testing.T) {
+	registry := metrics.NewRegistry()
+	handler := middleware.Metrics(registry)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req = req.WithContext(middleware.WithRoute(req.Context(), "/books/"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	buffer := &bytes.Buffer{}
+	registry.WriteText(buffer)
+
+	assert.Contains(t, buffer.String(), `http_requests_total{method="GET",route="/books/",status="404"} 1`)
+	assert.Contains(t, buffer.String(), `http_request_duration_seconds_count{method="GET",route="/books/",status="404"} 1`)
+}
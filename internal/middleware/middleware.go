@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type Middleware func(http.Handler) http.Handler
+
+type routeKey struct{}
+
+// Chain composes middlewares so that the first one is the outermost and sees
+// the request first.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+func RouteFromContext(ctx context.Context) string {
+	route, _ := ctx.Value(routeKey{}).(string)
+	return route
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, statusCode int, detail string, extensions map[string]interface{}) {
+	response := map[string]interface{}{
+		"type":     "about:blank",
+		"title":    http.StatusText(statusCode),
+		"status":   statusCode,
+		"instance": r.URL.Path,
+	}
+	if detail != "" {
+		response["detail"] = detail
+	}
+	for key, value := range extensions {
+		response[key] = value
+	}
+
+	jsonInBytes, _ := json.Marshal(response)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(statusCode)
+	w.Write(jsonInBytes)
+}
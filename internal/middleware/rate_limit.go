@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/ratelimit"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit enforces the limit configured for the request's route, falling back
+// to defaultLimit, per principal put on the context by Authenticate, so it must
+// run after it, or per client IP for anonymous callers. Unverified credentials
+// are never used as the key, so made-up API keys cannot buy fresh buckets.
+func RateLimit(store ratelimit.Store, routeLimits map[string]ratelimit.Limit, defaultLimit ratelimit.Limit) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := RouteFromContext(r.Context())
+			limit, exists := routeLimits[route]
+			if !exists {
+				limit = defaultLimit
+			}
+			if limit.IsUnlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), route+"|"+clientKey(r), limit)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to check rate limit", "error", err, "route", route)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+			if !result.Allowed {
+				w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+				writeProblem(w, r, http.StatusTooManyRequests, "Rate limit exceeded.", nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal.Method + ":" + principal.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/middleware"
+	"gojek/library-service-api/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRateLimitedRequest(route, remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, route, nil)
+	req.RemoteAddr = remoteAddr
+	return req.WithContext(middleware.WithRoute(req.Context(), route))
+}
+
+func TestRateLimit_GivenRouteLimitExceeded_ThenReturnTooManyRequestsWithHeaders(t *testing.T) {
+	handler := middleware.RateLimit(ratelimit.NewMemoryStore(),
+		map[string]ratelimit.Limit{"/books": {Rate: 1, Burst: 1}}, ratelimit.Limit{},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newRateLimitedRequest("/books", "10.0.0.1:1234"))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newRateLimitedRequest("/books", "10.0.0.1:5678"))
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, "1", second.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_GivenUnverifiedAPIKeysFromSameIP_ThenLimitByClientIP(t *testing.T) {
+	handler := middleware.RateLimit(ratelimit.NewMemoryStore(),
+		nil, ratelimit.Limit{Rate: 1, Burst: 1},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	first := newRateLimitedRequest("/books", "10.0.0.1:1234")
+	first.Header.Set(auth.APIKeyHeader, "first-key")
+	second := newRateLimitedRequest("/books", "10.0.0.1:1234")
+	second.Header.Set(auth.APIKeyHeader, "second-key")
+
+	firstRecorder, secondRecorder := httptest.NewRecorder(), httptest.NewRecorder()
+	handler.ServeHTTP(firstRecorder, first)
+	handler.ServeHTTP(secondRecorder, second)
+
+	assert.Equal(t, http.StatusOK, firstRecorder.Code)
+	assert.Equal(t, http.StatusTooManyRequests, secondRecorder.Code)
+}
+
+func TestRateLimit_GivenDifferentPrincipalsFromSameIP_ThenLimitEachPrincipalSeparately(t *testing.T) {
+	handler := middleware.RateLimit(ratelimit.NewMemoryStore(),
+		nil, ratelimit.Limit{Rate: 1, Burst: 1},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	first := newRateLimitedRequest("/books", "10.0.0.1:1234")
+	first = first.WithContext(auth.WithPrincipal(first.Context(), auth.Principal{Subject: "first", Method: "api_key"}))
+	second := newRateLimitedRequest("/books", "10.0.0.1:1234")
+	second = second.WithContext(auth.WithPrincipal(second.Context(), auth.Principal{Subject: "second", Method: "api_key"}))
+
+	firstRecorder, secondRecorder := httptest.NewRecorder(), httptest.NewRecorder()
+	handler.ServeHTTP(firstRecorder, first)
+	handler.ServeHTTP(secondRecorder, second)
+
+	assert.Equal(t, http.StatusOK, firstRecorder.Code)
+	assert.Equal(t, http.StatusOK, secondRecorder.Code)
+}
+
+func TestRateLimit_GivenUnlimitedRoute_ThenSkipRateLimitHeaders(t *testing.T) {
+	handler := middleware.RateLimit(ratelimit.NewMemoryStore(),
+		map[string]ratelimit.Limit{"/books": {Rate: 1, Burst: 1}}, ratelimit.Limit{},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRateLimitedRequest("/ping", "10.0.0.1:1234"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
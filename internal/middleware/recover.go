@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			slog.ErrorContext(r.Context(), "recovered from panic",
+				"panic", fmt.Sprint(recovered), "method", r.Method, "path", r.URL.Path, "stack", string(debug.Stack()))
+			writeProblem(w, r, http.StatusInternalServerError, "The server encountered an unexpected error.", nil)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
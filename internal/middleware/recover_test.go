@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover_GivenPanickingHandler_ThenReturnProblemResponse(t *testing.T) {
+	handler := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("unexpected")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	response := map[string]interface{}{}
+	err := json.NewDecoder(res.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+	assert.Equal(t, float64(http.StatusInternalServerError), response["status"])
+	assert.Equal(t, "/books", response["instance"])
+}
@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/logger"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID_GivenNoRequestIDHeader_ThenGenerateRequestID(t *testing.T) {
+	requestID := ""
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = logger.RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Len(t, requestID, 32)
+	assert.Equal(t, requestID, w.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestRequestID_GivenRequestIDHeader_ThenPropagateRequestID(t *testing.T) {
+	requestID := ""
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = logger.RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(middleware.RequestIDHeader, "request-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "request-123", requestID)
+	assert.Equal(t, "request-123", w.Header().Get(middleware.RequestIDHeader))
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import "net/http"
+
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode   int
+	bytesWritten int
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	if recorder, ok := w.(*responseRecorder); ok {
+		return recorder
+	}
+	return &responseRecorder{ResponseWriter: w}
+}
+
+func (recorder *responseRecorder) WriteHeader(statusCode int) {
+	if recorder.statusCode == 0 {
+		recorder.statusCode = statusCode
+	}
+	recorder.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	if recorder.statusCode == 0 {
+		recorder.statusCode = http.StatusOK
+	}
+	n, err := recorder.ResponseWriter.Write(data)
+	recorder.bytesWritten += n
+	return n, err
+}
+
+func (recorder *responseRecorder) Status() int {
+	if recorder.statusCode == 0 {
+		return http.StatusOK
+	}
+	return recorder.statusCode
+}
+
+func (recorder *responseRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/config"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SecurityHeaders sets HSTS and X-Content-Type-Options on every response, and
+// the Content-Security-Policy on HTML responses that do not set their own.
+func SecurityHeaders(securityConfig config.SecurityHeadersConfig) Middleware {
+	hsts := "max-age=" + strconv.Itoa(int(securityConfig.HSTSMaxAge.Seconds())) + "; includeSubDomains"
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if securityConfig.HSTSMaxAge > 0 {
+				w.Header().Set("Strict-Transport-Security", hsts)
+			}
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			next.ServeHTTP(&htmlPolicyWriter{ResponseWriter: w, contentSecurityPolicy: securityConfig.ContentSecurityPolicy}, r)
+		})
+	}
+}
+
+type htmlPolicyWriter struct {
+	http.ResponseWriter
+	contentSecurityPolicy string
+	wroteHeader           bool
+}
+
+func (writer *htmlPolicyWriter) WriteHeader(statusCode int) {
+	if !writer.wroteHeader {
+		writer.wroteHeader = true
+		isHTML := strings.HasPrefix(writer.Header().Get("Content-Type"), "text/html")
+		if isHTML && writer.contentSecurityPolicy != "" && writer.Header().Get("Content-Security-Policy") == "" {
+			writer.Header().Set("Content-Security-Policy", writer.contentSecurityPolicy)
+		}
+	}
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (writer *htmlPolicyWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		if writer.Header().Get("Content-Type") == "" {
+			writer.Header().Set("Content-Type", http.DetectContentType(data))
+		}
+		writer.WriteHeader(http.StatusOK)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *htmlPolicyWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
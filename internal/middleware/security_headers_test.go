@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/config"
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecurityHeadersHandler(contentType, body string) http.Handler {
+	securityConfig := config.SecurityHeadersConfig{HSTSMaxAge: time.Hour, ContentSecurityPolicy: "default-src 'self'"}
+	return middleware.SecurityHeaders(securityConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestSecurityHeaders_GivenJSONResponse_ThenSetHSTSAndNoSniffWithoutCSP(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSecurityHeadersHandler("application/json", `{}`).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, "max-age=3600; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+}
+
+func TestSecurityHeaders_GivenHTMLResponse_ThenSetContentSecurityPolicy(t *testing.T) {
+	w := httptest.NewRecorder()
+	newSecurityHeadersHandler("", "<!DOCTYPE html><html></html>").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+}
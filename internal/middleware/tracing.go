@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"gojek/library-service-api/internal/tracing"
+	"net/http"
+	"strconv"
+)
+
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := RouteFromContext(r.Context())
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), r.Method+" "+route)
+		defer span.End()
+
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.target", r.URL.Path)
+		tracing.Inject(ctx, w.Header())
+
+		recorder := newResponseRecorder(w)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		span.SetAttribute("http.status_code", strconv.Itoa(recorder.Status()))
+	})
+}
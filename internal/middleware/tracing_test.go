@@ -0,0 +1,35 @@
+package middleware_test
+
+import (
+	"gojek/library-service-api/internal/middleware"
+	"gojek/library-service-api/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracing_GivenTraceparentHeader_ThenContinueTraceAndRecordSpan(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracing.SetTracer(&tracing.Tracer{Exporter: exporter})
+	defer tracing.SetTracer(&tracing.Tracer{})
+
+	handler := middleware.Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/books", nil)
+	req.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req = req.WithContext(middleware.WithRoute(req.Context(), "/books"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "POST /books", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID)
+	assert.Equal(t, "201", spans[0].Attributes["http.status_code"])
+	assert.True(t, strings.Contains(w.Header().Get(tracing.TraceparentHeader), spans[0].SpanID))
+}
@@ -0,0 +1,43 @@
+(function () {
+  var container = document.getElementById("docs");
+  var element = function (tag, className, text) {
+    var node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text) node.textContent = text;
+    return node;
+  };
+
+  fetch("/openapi.json").then(function (response) {
+    return response.json();
+  }).then(function (spec) {
+    container.textContent = "";
+    container.appendChild(element("h1", "", spec.info.title + " " + spec.info.version));
+    container.appendChild(element("p", "", spec.info.description || ""));
+
+    Object.keys(spec.paths).forEach(function (path) {
+      var item = spec.paths[path];
+      if (item.$ref) item = spec.paths[item.$ref.replace("#/paths/", "").replace(/~1/g, "/").replace(/~0/g, "~")];
+      ["get", "post", "put", "delete"].forEach(function (method) {
+        var operation = item[method];
+        if (!operation) return;
+        var details = element("details", "operation");
+        var summary = element("summary");
+        summary.appendChild(element("span", "method " + method, method));
+        summary.appendChild(document.createTextNode(path + " — " + (operation.summary || "") + (operation.deprecated ? " (deprecated)" : "")));
+        details.appendChild(summary);
+        details.appendChild(element("pre", "", JSON.stringify(operation, null, 2)));
+        container.appendChild(details);
+      });
+    });
+
+    container.appendChild(element("h2", "", "Schemas"));
+    Object.keys(spec.components.schemas).forEach(function (name) {
+      var details = element("details", "operation");
+      details.appendChild(element("summary", "", name));
+      details.appendChild(element("pre", "", JSON.stringify(spec.components.schemas[name], null, 2)));
+      container.appendChild(details);
+    });
+  }).catch(function (error) {
+    container.textContent = "Unable to load /openapi.json: " + error;
+  });
+})();
@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+//go:embed openapi.json
+var Document []byte
+
+//go:embed docs.js
+var docsScript string
+
+var (
+	docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Library Service API</title>
+<style>` + docsStyle + `</style>
+</head>
+<body>
+<main id="docs"><p>Loading API documentation&hellip;</p></main>
+<script>` + docsScript + `</script>
+</body>
+</html>
+`
+	docsContentSecurityPolicy = "default-src 'self'; script-src '" + hashSource(docsScript) + "'; style-src '" + hashSource(docsStyle) + "'; frame-ancestors 'none'"
+)
+
+const docsStyle = `body{font-family:system-ui,sans-serif;margin:0 auto;max-width:960px;padding:1rem;color:#1f2328}
+h1{margin-bottom:0}.operation{border:1px solid #d0d7de;border-radius:6px;margin:.5rem 0;padding:.5rem 1rem}
+.method{display:inline-block;min-width:4.5rem;font-weight:bold;text-transform:uppercase}
+.get{color:#0969da}.post{color:#1a7f37}.put{color:#9a6700}.delete{color:#cf222e}
+pre{background:#f6f8fa;padding:.5rem;overflow:auto}summary{cursor:pointer}`
+
+func HandleDocumentRequest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(Document)
+}
+
+// HandleDocsRequest serves a self-contained page that renders the document
+// from /openapi.json. Its inline script and style are allowed by hash so the
+// page works under a strict Content-Security-Policy.
+func HandleDocsRequest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", docsContentSecurityPolicy)
+	w.Write([]byte(docsPage))
+}
+
+func hashSource(source string) string {
+	digest := sha256.Sum256([]byte(source))
+	return "sha256-" + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// Path converts a route pattern registered on the router into the path it is
+// documented under, e.g. "/books/" into "/books/{id}".
+func Path(pattern string) string {
+	if pattern != "/" && strings.HasSuffix(pattern, "/") {
+		return pattern + "{id}"
+	}
+	return pattern
+}
@@ -0,0 +1,47 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"gojek/library-service-api/internal/openapi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleDocumentRequest_GivenNothing_ThenReturnOpenAPIDocument(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	openapi.HandleDocumentRequest(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	document := map[string]interface{}{}
+	err := json.NewDecoder(res.Body).Decode(&document)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Equal(t, "3.1.0", document["openapi"])
+}
+
+func TestHandleDocsRequest_GivenNothing_ThenReturnHTMLPageAllowedByItsPolicy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+	w := httptest.NewRecorder()
+	openapi.HandleDocsRequest(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	data, _ := io.ReadAll(res.Body)
+
+	assert.True(t, strings.HasPrefix(res.Header.Get("Content-Type"), "text/html"))
+	assert.Contains(t, string(data), `fetch("/openapi.json")`)
+	assert.Contains(t, res.Header.Get("Content-Security-Policy"), "script-src 'sha256-")
+}
+
+func TestPath_GivenSubtreePattern_ThenReturnPathWithIDParameter(t *testing.T) {
+	assert.Equal(t, "/books/{id}", openapi.Path("/books/"))
+	assert.Equal(t, "/books", openapi.Path("/books"))
+}
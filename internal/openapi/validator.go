@@ -0,0 +1,313 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError describes where a request or response departs from the
+// document: Path points into the message body and SchemaPointer into the
+// OpenAPI document.
+type ValidationError struct {
+	Operation     string
+	Path          string
+	SchemaPointer string
+	Message       string
+}
+
+func (validationError *ValidationError) Error() string {
+	return fmt.Sprintf("%s: body %q does not match %s: %s",
+		validationError.Operation, validationError.Path, validationError.SchemaPointer, validationError.Message)
+}
+
+// Validator checks HTTP messages against the subset of OpenAPI 3.1 and JSON
+// Schema used by this service's document: type, required, properties,
+// additionalProperties, items, minLength, maxLength, enum, and the date-time
+// and int64 formats. Other formats are treated as annotations.
+type Validator struct {
+	document map[string]interface{}
+}
+
+func NewValidator(document []byte) (*Validator, error) {
+	validator := &Validator{}
+	if err := json.Unmarshal(document, &validator.document); err != nil {
+		return nil, fmt.Errorf("parse openapi document: %w", err)
+	}
+	return validator, nil
+}
+
+func (validator *Validator) ValidateRequest(r *http.Request, body []byte) error {
+	operation, pointer, err := validator.findOperation(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+	name := r.Method + " " + r.URL.Path
+
+	requestBody, exists := operation["requestBody"].(map[string]interface{})
+	if !exists {
+		return nil
+	}
+	requestBody, pointer = validator.resolve(requestBody, pointer+"/requestBody")
+	if len(body) == 0 {
+		if required, _ := requestBody["required"].(bool); required {
+			return &ValidationError{Operation: name, Path: "", SchemaPointer: pointer, Message: "request body is required"}
+		}
+		return nil
+	}
+	return validator.validateContent(name, requestBody, pointer, r.Header.Get("Content-Type"), body)
+}
+
+func (validator *Validator) ValidateResponse(r *http.Request, statusCode int, header http.Header, body []byte) error {
+	operation, pointer, err := validator.findOperation(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+	name := r.Method + " " + r.URL.Path
+
+	responses, _ := operation["responses"].(map[string]interface{})
+	response, exists := responses[strconv.Itoa(statusCode)].(map[string]interface{})
+	if !exists {
+		return &ValidationError{Operation: name, SchemaPointer: pointer + "/responses",
+			Message: fmt.Sprintf("status %d is not documented", statusCode)}
+	}
+	response, pointer = validator.resolve(response, pointer+"/responses/"+strconv.Itoa(statusCode))
+	if _, hasContent := response["content"]; !hasContent {
+		return nil
+	}
+	return validator.validateContent(name, response, pointer, header.Get("Content-Type"), body)
+}
+
+// findOperation matches path against the concrete paths before the templated
+// ones, as OpenAPI requires, so /books/events is not taken for /books/{id}.
+// Among matching templates the one with the earliest literal segment wins, so
+// the choice does not depend on map order.
+func (validator *Validator) findOperation(method, path string) (map[string]interface{}, string, error) {
+	paths, _ := validator.document["paths"].(map[string]interface{})
+	item, exists := paths[path]
+	template := path
+	if !exists {
+		for candidate, candidateItem := range paths {
+			if matchesTemplate(candidate, path) && (!exists || moreSpecific(candidate, template)) {
+				item, exists, template = candidateItem, true, candidate
+			}
+		}
+	}
+	if !exists {
+		return nil, "", &ValidationError{Operation: method + " " + path, SchemaPointer: "#/paths", Message: "path is not documented"}
+	}
+
+	operations, _ := item.(map[string]interface{})
+	operations, pointer := validator.resolve(operations, "#/paths/"+escapePointer(template))
+	operation, exists := operations[strings.ToLower(method)].(map[string]interface{})
+	if !exists {
+		return nil, "", &ValidationError{Operation: method + " " + path, SchemaPointer: pointer,
+			Message: "method is not documented"}
+	}
+	return operation, pointer + "/" + strings.ToLower(method), nil
+}
+
+func (validator *Validator) validateContent(name string, message map[string]interface{}, pointer, contentType string, body []byte) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	content, _ := message["content"].(map[string]interface{})
+	media, exists := content[mediaType].(map[string]interface{})
+	if !exists {
+		return &ValidationError{Operation: name, SchemaPointer: pointer + "/content",
+			Message: fmt.Sprintf("content type %q is not documented", contentType)}
+	}
+	schema, _ := media["schema"].(map[string]interface{})
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Operation: name, SchemaPointer: pointer + "/content/" + escapePointer(mediaType),
+			Message: "body is not valid JSON: " + err.Error()}
+	}
+	if message := validator.validateValue(value, schema, "", pointer+"/content/"+escapePointer(mediaType)+"/schema"); message != nil {
+		message.Operation = name
+		return message
+	}
+	return nil
+}
+
+func (validator *Validator) validateValue(value interface{}, schema map[string]interface{}, path, pointer string) *ValidationError {
+	schema, pointer = validator.resolve(schema, pointer)
+	mismatch := func(pointerSuffix, format string, args ...interface{}) *ValidationError {
+		return &ValidationError{Path: path, SchemaPointer: pointer + pointerSuffix, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if expectedType, exists := schema["type"]; exists && !matchesType(value, expectedType) {
+		return mismatch("/type", "expected %v, got %s", expectedType, jsonType(value))
+	}
+	if enum, exists := schema["enum"].([]interface{}); exists && !containsValue(enum, value) {
+		return mismatch("/enum", "value is not one of %v", enum)
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, exists := typedValue[name.(string)]; !exists {
+				return mismatch("/required", "missing required property %q", name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, propertyValue := range typedValue {
+			propertySchema, exists := properties[name].(map[string]interface{})
+			if !exists {
+				if additional, isBool := schema["additionalProperties"].(bool); isBool && !additional {
+					return &ValidationError{Path: path + "/" + escapePointer(name), SchemaPointer: pointer + "/additionalProperties",
+						Message: fmt.Sprintf("property %q is not allowed", name)}
+				}
+				continue
+			}
+			if err := validator.validateValue(propertyValue, propertySchema, path+"/"+escapePointer(name), pointer+"/properties/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range typedValue {
+			if items == nil {
+				break
+			}
+			if err := validator.validateValue(item, items, path+"/"+strconv.Itoa(i), pointer+"/items"); err != nil {
+				return err
+			}
+		}
+	case string:
+		if minLength, exists := schema["minLength"].(float64); exists && float64(len([]rune(typedValue))) < minLength {
+			return mismatch("/minLength", "string is shorter than %v characters", minLength)
+		}
+		if maxLength, exists := schema["maxLength"].(float64); exists && float64(len([]rune(typedValue))) > maxLength {
+			return mismatch("/maxLength", "string is longer than %v characters", maxLength)
+		}
+		if format, _ := schema["format"].(string); format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, typedValue); err != nil {
+				return mismatch("/format", "string is not an RFC 3339 date-time")
+			}
+		}
+	case json.Number:
+		if format, _ := schema["format"].(string); format == "int64" {
+			if _, err := strconv.ParseInt(typedValue.String(), 10, 64); err != nil {
+				return mismatch("/format", "number is not a 64-bit integer")
+			}
+		}
+	}
+	return nil
+}
+
+// resolve follows a local $ref, returning the referenced object and its pointer.
+func (validator *Validator) resolve(object map[string]interface{}, pointer string) (map[string]interface{}, string) {
+	for {
+		ref, isRef := object["$ref"].(string)
+		if !isRef || !strings.HasPrefix(ref, "#/") {
+			return object, pointer
+		}
+		var current interface{} = validator.document
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			parent, _ := current.(map[string]interface{})
+			current = parent[token]
+		}
+		resolved, _ := current.(map[string]interface{})
+		if resolved == nil {
+			return object, pointer
+		}
+		object, pointer = resolved, ref
+	}
+}
+
+func matchesTemplate(template, path string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range templateSegments {
+		isParameter := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+		if !isParameter && segment != pathSegments[i] {
+			return false
+		}
+		if isParameter && pathSegments[i] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// moreSpecific reports whether template a should be preferred over b, which
+// matches the same number of segments: a literal segment beats a parameter at
+// the first position where they differ, and otherwise the lower string wins.
+func moreSpecific(a, b string) bool {
+	aSegments := strings.Split(strings.Trim(a, "/"), "/")
+	bSegments := strings.Split(strings.Trim(b, "/"), "/")
+	for i := range aSegments {
+		aParameter := strings.HasPrefix(aSegments[i], "{")
+		bParameter := strings.HasPrefix(bSegments[i], "{")
+		if aParameter != bParameter {
+			return !aParameter
+		}
+	}
+	return a < b
+}
+
+func matchesType(value interface{}, expectedType interface{}) bool {
+	if types, isList := expectedType.([]interface{}); isList {
+		for _, candidate := range types {
+			if matchesType(value, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	actualType := jsonType(value)
+	return actualType == expectedType || (expectedType == "number" && actualType == "integer")
+}
+
+// containsValue compares JSON values as JSON does, so 1 and 1.0 are equal.
+func containsValue(values []interface{}, value interface{}) bool {
+	if number, isNumber := value.(json.Number); isNumber {
+		value, _ = number.Float64()
+	}
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if number, err := typedValue.Float64(); err == nil && number == math.Trunc(number) && !strings.ContainsAny(typedValue.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
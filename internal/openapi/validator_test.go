@@ -0,0 +1,210 @@
+package openapi_test
+
+import (
+	"errors"
+	"gojek/library-service-api/internal/openapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupValidator(t *testing.T) *openapi.Validator {
+	validator, err := openapi.NewValidator(openapi.Document)
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI document: %v", err)
+	}
+	return validator
+}
+
+func jsonHeader() http.Header {
+	return http.Header{"Content-Type": []string{"application/json"}}
+}
+
+func TestValidateRequest_GivenValidAddBookRequest_ThenReturnNil(t *testing.T) {
+	validator := setupValidator(t)
+	body := `{"title":"Clean Code","price":10.99,"publishedDate":"1990-06-01"}`
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	assert.NoError(t, validator.ValidateRequest(req, []byte(body)))
+}
+
+func TestValidateRequest_GivenWrongPropertyType_ThenReportBodyPathAndSchemaPointer(t *testing.T) {
+	validator := setupValidator(t)
+	body := `{"title":1234}`
+	req := httptest.NewRequest(http.MethodPut, "/books/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	err := validator.ValidateRequest(req, []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "PUT /books/1", validationErr.Operation)
+	assert.Equal(t, "/title", validationErr.Path)
+	assert.Equal(t, "#/components/schemas/UpdateBookTitleRequest/properties/title/type", validationErr.SchemaPointer)
+}
+
+func TestValidateRequest_GivenUndocumentedPath_ThenReturnError(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/authors", nil)
+
+	err := validator.ValidateRequest(req, nil)
+
+	assert.ErrorContains(t, err, "path is not documented")
+}
+
+func TestValidateRequest_GivenUndocumentedMethod_ThenReturnError(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodPatch, "/books/1", nil)
+
+	err := validator.ValidateRequest(req, nil)
+
+	assert.ErrorContains(t, err, "method is not documented")
+}
+
+func TestValidateResponse_GivenBareBook_ThenReturnNil(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	body := `{"id":1,"title":"Clean Code","price":10.99,"publishedDate":"1990-06-01"}`
+
+	assert.NoError(t, validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(body)))
+}
+
+func TestValidateResponse_GivenUndocumentedProperty_ThenReportBodyPathAndSchemaPointer(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	body := `{"id":1,"title":"Clean Code","price":10.99,"publishedDate":"1990-06-01","message":"Book found."}`
+
+	err := validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "/message", validationErr.Path)
+	assert.Equal(t, "#/components/schemas/Book/additionalProperties", validationErr.SchemaPointer)
+}
+
+func TestValidateResponse_GivenMissingRequiredProperty_ThenReturnError(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodDelete, "/books/1", nil)
+
+	err := validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(`{"id":1}`))
+
+	assert.ErrorContains(t, err, `missing required property "message"`)
+}
+
+func TestValidateResponse_GivenBookListItemWithWrongType_ThenReportItemPath(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	body := `{"books":[{"id":1,"title":"Clean Code","price":"10.99","publishedDate":"1990-06-01"}]}`
+
+	err := validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "/books/0/price", validationErr.Path)
+	assert.Equal(t, "#/components/schemas/Book/properties/price/type", validationErr.SchemaPointer)
+}
+
+func TestValidateResponse_GivenUndocumentedStatus_ThenReturnError(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+
+	err := validator.ValidateResponse(req, http.StatusTeapot, jsonHeader(), []byte(`{}`))
+
+	assert.ErrorContains(t, err, "status 418 is not documented")
+}
+
+func TestValidateResponse_GivenUndocumentedContentType_ThenReturnError(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	header := http.Header{"Content-Type": []string{"text/plain"}}
+
+	err := validator.ValidateResponse(req, http.StatusOK, header, []byte("pong"))
+
+	assert.ErrorContains(t, err, `content type "text/plain" is not documented`)
+}
+
+func TestValidateResponse_GivenPathItemReference_ThenValidateAgainstReferencedOperation(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodPost, "/v1/books", nil)
+	body := `{"id":1,"title":"Clean Code","price":10.99,"publishedDate":"1990-06-01"}`
+
+	err := validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "#/components/schemas/AddBookResponse/required", validationErr.SchemaPointer)
+}
+
+func TestValidateRequest_GivenEmptyTitle_ThenReportMinLength(t *testing.T) {
+	validator := setupValidator(t)
+	body := `{"title":""}`
+	req := httptest.NewRequest(http.MethodPut, "/v2/books/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	err := validator.ValidateRequest(req, []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "#/components/schemas/UpdateBookRequest/properties/title/minLength", validationErr.SchemaPointer)
+}
+
+func TestValidateResponse_GivenConcretePathAlsoMatchingTemplate_ThenUseConcretePath(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/books/events", nil)
+	header := http.Header{"Content-Type": []string{"text/event-stream"}}
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, validator.ValidateResponse(req, http.StatusOK, header, []byte(": heartbeat\n\n")))
+	}
+}
+
+func TestValidateRequest_GivenValueOutsideEnum_ThenReportEnum(t *testing.T) {
+	validator := setupValidator(t)
+	body := `{"url":"https://partner.example/hooks","events":["BookMoved"]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	err := validator.ValidateRequest(req, []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "/events/0", validationErr.Path)
+	assert.Equal(t, "#/components/schemas/CreateWebhookRequest/properties/events/items/enum", validationErr.SchemaPointer)
+}
+
+func TestValidateResponse_GivenMalformedDateTime_ThenReportFormat(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/1", nil)
+	body := `{"id":1,"url":"https://partner.example/hooks","events":[],"createdAt":"yesterday"}`
+
+	err := validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(body))
+
+	validationErr := &openapi.ValidationError{}
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "#/components/schemas/Webhook/properties/createdAt/format", validationErr.SchemaPointer)
+}
+
+func TestValidateResponse_GivenRFC3339DateTime_ThenReturnNil(t *testing.T) {
+	validator := setupValidator(t)
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/1", nil)
+	body := `{"id":1,"url":"https://partner.example/hooks","events":["BookCreated"],"createdAt":"2024-01-01T10:00:00.123Z"}`
+
+	assert.NoError(t, validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(body)))
+}
+
+func TestValidateResponse_GivenTwoMatchingTemplates_ThenAlwaysUseTheOneWithLiteralSegment(t *testing.T) {
+	validator, err := openapi.NewValidator([]byte(`{"paths":{
+		"/items/{id}/{view}":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"string"}}}}}}},
+		"/items/{id}/history":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"array"}}}}}}}
+	}}`))
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/items/1/history", nil)
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, validator.ValidateResponse(req, http.StatusOK, jsonHeader(), []byte(`[]`)))
+	}
+}
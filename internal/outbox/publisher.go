@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Message is the JSON document publishers send for an event. Events the
+// database announces directly, rather than through the outbox, have no ID.
+type Message struct {
+	ID         int64           `json:"id,omitempty"`
+	Type       string          `json:"type"`
+	BookID     int             `json:"book_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func NewMessage(event domain.Event) Message {
+	return Message{ID: event.ID, Type: event.Type, BookID: event.BookID, OccurredAt: event.OccurredAt, Data: event.Payload}
+}
+
+// LogPublisher writes events to the log, for development or as a record when
+// no consumer is configured.
+type LogPublisher struct {
+	Logger *slog.Logger
+}
+
+func (publisher *LogPublisher) Publish(ctx context.Context, event domain.Event) error {
+	logger := publisher.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.InfoContext(ctx, "book event", "event_id", event.ID, "type", event.Type,
+		"book_id", event.BookID, "data", string(event.Payload))
+	return nil
+}
+
+// WebhookPublisher POSTs each event as a Message to URL. Any response other
+// than 2xx counts as a failed delivery.
+type WebhookPublisher struct {
+	URL    string
+	Client *http.Client
+}
+
+func (publisher *WebhookPublisher) Publish(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, publisher.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Event-Type", event.Type)
+
+	client := publisher.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return nil
+}
+
+// PubSubPublisher publishes each event as a Message on a channel of a message
+// broker, e.g. Redis pub/sub.
+type PubSubPublisher struct {
+	PubSub  cache.PubSub
+	Channel string
+}
+
+func (publisher *PubSubPublisher) Publish(ctx context.Context, event domain.Event) error {
+	message, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+	return publisher.PubSub.Publish(ctx, publisher.Channel, string(message))
+}
+
+// Publishers passes each event to every publisher in turn. An event one of
+// them rejects is offered to all of them again, so each must tolerate
+// duplicates.
+type Publishers []Publisher
+
+func (publishers Publishers) Publish(ctx context.Context, event domain.Event) error {
+	for _, publisher := range publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
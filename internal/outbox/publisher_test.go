@@ -0,0 +1,89 @@
+package outbox_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/cache/resptest"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/outbox"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var bookCreated = domain.Event{ID: 7, Type: domain.EventBookCreated, BookID: 3,
+	Payload: []byte(`{"id":3,"title":"Dune"}`), OccurredAt: time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)}
+
+func TestWebhookPublisher_GivenEvent_ThenPostMessage(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	err := (&outbox.WebhookPublisher{URL: receiver.URL}).Publish(context.Background(), bookCreated)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "7", received.Header.Get("X-Event-ID"))
+	assert.Equal(t, domain.EventBookCreated, received.Header.Get("X-Event-Type"))
+	assert.JSONEq(t, `{"id":7,"type":"BookCreated","book_id":3,"occurred_at":"2026-03-01T10:30:00Z","data":{"id":3,"title":"Dune"}}`, string(body))
+}
+
+func TestWebhookPublisher_GivenErrorStatus_ThenReturnError(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	err := (&outbox.WebhookPublisher{URL: receiver.URL}).Publish(context.Background(), bookCreated)
+
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestPubSubPublisher_GivenEvent_ThenPublishMessageOnChannel(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	publisher := &outbox.PubSubPublisher{PubSub: cache.NewRedisCache(server.Addr, "", 0), Channel: "library:books:events"}
+
+	assert.NoError(t, publisher.Publish(context.Background(), bookCreated))
+
+	command := server.Commands()[0]
+	assert.Equal(t, []string{"PUBLISH", "library:books:events"}, command[:2])
+	message := outbox.Message{}
+	assert.NoError(t, json.Unmarshal([]byte(command[2]), &message))
+	assert.Equal(t, outbox.NewMessage(bookCreated).ID, message.ID)
+	assert.JSONEq(t, string(bookCreated.Payload), string(message.Data))
+}
+
+func TestLogPublisher_GivenEvent_ThenLogIt(t *testing.T) {
+	output := &bytes.Buffer{}
+	publisher := &outbox.LogPublisher{Logger: slog.New(slog.NewJSONHandler(output, nil))}
+
+	assert.NoError(t, publisher.Publish(context.Background(), bookCreated))
+
+	entry := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &entry))
+	assert.Equal(t, "BookCreated", entry["type"])
+	assert.Equal(t, float64(3), entry["book_id"])
+}
+
+func TestPublishers_GivenFailingPublisher_ThenStopAndReturnError(t *testing.T) {
+	first, failing, last := &recordingPublisher{}, &recordingPublisher{failOn: bookCreated.ID}, &recordingPublisher{}
+
+	err := outbox.Publishers{first, failing, last}.Publish(context.Background(), bookCreated)
+
+	assert.Error(t, err)
+	assert.Equal(t, []int64{bookCreated.ID}, first.published)
+	assert.Empty(t, last.published)
+}
@@ -0,0 +1,69 @@
+// Package outbox relays the book events stored in the outbox table to
+// downstream consumers through a pluggable Publisher.
+package outbox
+
+import (
+	"context"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/metrics"
+	"log/slog"
+	"time"
+)
+
+var PublishAttempts = metrics.NewCounterVec("outbox_publish_attempts_total",
+	"Outbox events passed to the publisher, by event type and result.", "type", "result")
+
+// Store is implemented by repository.OutboxRepository.
+type Store interface {
+	RelayEvents(ctx context.Context, limit int, publish func(domain.Event) error) (int, error)
+}
+
+// Publisher delivers one event. An event counts as delivered once Publish
+// returns nil; until then it is offered again, so consumers see every event at
+// least once.
+type Publisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+type Relay struct {
+	Store     Store
+	Publisher Publisher
+	BatchSize int
+	Interval  time.Duration
+}
+
+// Run relays pending events every Interval until ctx is done. An event the
+// publisher rejects holds back the ones after it until the next interval.
+func (relay *Relay) Run(ctx context.Context) {
+	for {
+		if _, err := relay.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "failed to relay outbox events", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(relay.Interval):
+		}
+	}
+}
+
+// RelayPending publishes batches of events until the outbox is drained or an
+// event fails, and returns how many were published.
+func (relay *Relay) RelayPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		published, err := relay.Store.RelayEvents(ctx, relay.BatchSize, func(event domain.Event) error {
+			err := relay.Publisher.Publish(ctx, event)
+			result := "success"
+			if err != nil {
+				result = "failure"
+			}
+			PublishAttempts.WithLabelValues(event.Type, result).Inc()
+			return err
+		})
+		total += published
+		if err != nil || published < relay.BatchSize {
+			return total, err
+		}
+	}
+}
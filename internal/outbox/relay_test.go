@@ -0,0 +1,82 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/outbox"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore relays events the way repository.OutboxRepository does, without
+// a database.
+type memoryStore struct {
+	events    []domain.Event
+	published int
+	calls     int
+}
+
+func (store *memoryStore) RelayEvents(ctx context.Context, limit int, publish func(domain.Event) error) (int, error) {
+	store.calls++
+	published := 0
+	for _, event := range store.events[store.published:min(len(store.events), store.published+limit)] {
+		if err := publish(event); err != nil {
+			return published, err
+		}
+		store.published++
+		published++
+	}
+	return published, nil
+}
+
+type recordingPublisher struct {
+	published []int64
+	failOn    int64
+}
+
+func (publisher *recordingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	if event.ID == publisher.failOn {
+		return errors.New("broker unavailable")
+	}
+	publisher.published = append(publisher.published, event.ID)
+	return nil
+}
+
+func newMemoryStore(count int) *memoryStore {
+	store := &memoryStore{}
+	for id := 1; id <= count; id++ {
+		store.events = append(store.events, domain.Event{ID: int64(id), Type: domain.EventBookCreated, BookID: id})
+	}
+	return store
+}
+
+func TestRelayPending_GivenSeveralBatches_ThenPublishAllInOrder(t *testing.T) {
+	store := newMemoryStore(5)
+	publisher := &recordingPublisher{}
+	relay := &outbox.Relay{Store: store, Publisher: publisher, BatchSize: 2}
+
+	published, err := relay.RelayPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, published)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, publisher.published)
+	assert.Equal(t, 3, store.calls)
+}
+
+func TestRelayPending_GivenPublishFailure_ThenStopAndRetryFailedEventFirst(t *testing.T) {
+	store := newMemoryStore(4)
+	publisher := &recordingPublisher{failOn: 3}
+	relay := &outbox.Relay{Store: store, Publisher: publisher, BatchSize: 10}
+
+	published, err := relay.RelayPending(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 2, published)
+
+	publisher.failOn = 0
+	published, err = relay.RelayPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{1, 2, 3, 4}, publisher.published)
+}
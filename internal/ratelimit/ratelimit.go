@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit describes a token bucket that refills at Rate tokens per second and
+// holds at most Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (limit Limit) IsUnlimited() bool {
+	return limit.Rate <= 0 || limit.Burst <= 0
+}
+
+// ParseLimit reads a limit written as "<rate per second>:<burst>", e.g. "5:10".
+func ParseLimit(value string) (Limit, error) {
+	rate, burst, found := strings.Cut(value, ":")
+	if !found {
+		return Limit{}, fmt.Errorf("parse rate limit %q: expected <rate>:<burst>", value)
+	}
+	limit := Limit{}
+	var err error
+	if limit.Rate, err = strconv.ParseFloat(rate, 64); err != nil {
+		return Limit{}, fmt.Errorf("parse rate limit %q: %w", value, err)
+	}
+	if limit.Burst, err = strconv.Atoi(burst); err != nil {
+		return Limit{}, fmt.Errorf("parse rate limit %q: %w", value, err)
+	}
+	return limit, nil
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// Store takes tokens from the bucket identified by key. Implementations backed
+// by a shared store let several replicas enforce one limit.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens     float64
+	updatedAt  time.Time
+	refillTime time.Duration
+}
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	Now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, Now: time.Now}
+}
+
+func (store *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.Now()
+	store.sweep(now)
+
+	currentBucket, exists := store.buckets[key]
+	if !exists {
+		currentBucket = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		store.buckets[key] = currentBucket
+	}
+	elapsed := now.Sub(currentBucket.updatedAt).Seconds()
+	currentBucket.tokens = math.Min(float64(limit.Burst), currentBucket.tokens+elapsed*limit.Rate)
+	currentBucket.updatedAt = now
+	currentBucket.refillTime = secondsToDuration(float64(limit.Burst) / limit.Rate)
+
+	result := Result{}
+	if currentBucket.tokens >= 1 {
+		currentBucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - currentBucket.tokens) / limit.Rate)
+	}
+	result.Remaining = int(currentBucket.tokens)
+	result.ResetAfter = secondsToDuration((float64(limit.Burst) - currentBucket.tokens) / limit.Rate)
+	return result, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again, so the
+// store does not grow with every client it has ever seen.
+func (store *MemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < time.Minute {
+		return
+	}
+	store.lastSweep = now
+	for key, idleBucket := range store.buckets {
+		if now.Sub(idleBucket.updatedAt) > idleBucket.refillTime {
+			delete(store.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,57 @@
+package ratelimit_test
+
+import (
+	"context"
+	"gojek/library-service-api/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit_GivenRateAndBurst_ThenReturnLimit(t *testing.T) {
+	limit, err := ratelimit.ParseLimit("0.5:10")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ratelimit.Limit{Rate: 0.5, Burst: 10}, limit)
+}
+
+func TestParseLimit_GivenInvalidValue_ThenReturnError(t *testing.T) {
+	for _, value := range []string{"", "5", "five:10", "5:ten"} {
+		_, err := ratelimit.ParseLimit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTake_GivenBurstExhausted_ThenRejectUntilTokensRefill(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := ratelimit.NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+
+	first, _ := store.Take(context.Background(), "client", limit)
+	second, _ := store.Take(context.Background(), "client", limit)
+	third, _ := store.Take(context.Background(), "client", limit)
+
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, time.Second, third.RetryAfter)
+	assert.Equal(t, 2*time.Second, third.ResetAfter)
+
+	now = now.Add(time.Second)
+	fourth, _ := store.Take(context.Background(), "client", limit)
+	assert.True(t, fourth.Allowed)
+}
+
+func TestTake_GivenDifferentKeys_ThenUseSeparateBuckets(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 1, Burst: 1}
+
+	first, _ := store.Take(context.Background(), "first-client", limit)
+	second, _ := store.Take(context.Background(), "second-client", limit)
+
+	assert.True(t, first.Allowed)
+	assert.True(t, second.Allowed)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository struct {
+	DB *sql.DB
+}
+
+func (apiKeyRepository *APIKeyRepository) FindAPIKeyByHash(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	apiKey := domain.APIKey{}
+	err := apiKeyRepository.DB.QueryRowContext(ctx,
+		"SELECT id, name, key_hash, subject, roles FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL", keyHash).
+		Scan(&apiKey.ID, &apiKey.Name, &apiKey.KeyHash, &apiKey.Subject, pq.Array(&apiKey.Roles))
+	return apiKey, queryError(ctx, "find api key", err)
+}
@@ -0,0 +1,63 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/repository"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAPIKeysTable(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`
+        CREATE TABLE IF NOT EXISTS api_keys (
+            id SERIAL PRIMARY KEY,
+            name VARCHAR(100) NOT NULL,
+            key_hash CHAR(64) NOT NULL UNIQUE,
+            subject VARCHAR(100) NOT NULL,
+            roles TEXT[] NOT NULL DEFAULT '{}',
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            revoked_at TIMESTAMPTZ
+        );
+    `)
+	if err != nil {
+		t.Fatalf("Failed to create api_keys table: %v", err)
+	}
+}
+
+func TestFindAPIKeyByHash_GivenStoredKey_ThenReturnAPIKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupAPIKeysTable(t, db)
+
+	keyHash := auth.HashAPIKey("secret-key")
+	db.Exec("INSERT INTO api_keys (name, key_hash, subject, roles) VALUES ($1, $2, $3, $4)",
+		"importer", keyHash, "catalogue-importer", pq.Array([]string{"librarian"}))
+
+	apiKeyRepository := &repository.APIKeyRepository{DB: db}
+	apiKey, err := apiKeyRepository.FindAPIKeyByHash(context.Background(), keyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "catalogue-importer", apiKey.Subject)
+	assert.Equal(t, []string{"librarian"}, apiKey.Roles)
+
+	db.Exec("DELETE FROM api_keys WHERE key_hash = $1", keyHash)
+}
+
+func TestFindAPIKeyByHash_GivenRevokedKey_ThenReturnError(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupAPIKeysTable(t, db)
+
+	keyHash := auth.HashAPIKey("revoked-key")
+	db.Exec("INSERT INTO api_keys (name, key_hash, subject, revoked_at) VALUES ($1, $2, $3, NOW())",
+		"revoked", keyHash, "former-partner")
+
+	apiKeyRepository := &repository.APIKeyRepository{DB: db}
+	_, err := apiKeyRepository.FindAPIKeyByHash(context.Background(), keyHash)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	db.Exec("DELETE FROM api_keys WHERE key_hash = $1", keyHash)
+}
@@ -1,45 +1,290 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/metrics"
+	"gojek/library-service-api/internal/tracing"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var QueryDuration = metrics.NewHistogramVec("db_query_duration_seconds",
+	"Duration of repository queries in seconds.", metrics.DefaultBuckets, "repository", "method")
+
+var (
+	ErrQueryCanceled = errors.New("query canceled")
+	ErrQueryTimeout  = errors.New("query timed out")
 )
 
+// BookFilter narrows SearchBooks; zero fields do not filter. Dates are
+// compared as YYYY-MM-DD.
+type BookFilter struct {
+	TitleContains   string
+	MinPrice        *float64
+	MaxPrice        *float64
+	PublishedAfter  string
+	PublishedBefore string
+	Limit           int
+	Offset          int
+}
+
+// BookStore is what the HTTP, GraphQL and gRPC layers need from book
+// storage, so BookRepository can be wrapped, for example by CachedBookStore.
+type BookStore interface {
+	FindAllBooks(ctx context.Context) ([]domain.Book, error)
+	StreamAllBooks(ctx context.Context, visit func(domain.Book) error) error
+	FindBookByID(ctx context.Context, id int) (domain.Book, error)
+	FindBooksByIDs(ctx context.Context, ids []int) ([]domain.Book, error)
+	SearchBooks(ctx context.Context, filter BookFilter) ([]domain.Book, int, error)
+	SaveBook(ctx context.Context, book *domain.Book) error
+	UpdateBookTitle(ctx context.Context, id int, title string) error
+	DeleteBookByID(ctx context.Context, id int) error
+}
+
 type BookRepository struct {
-	DB *sql.DB
+	DB           *sql.DB
+	QueryTimeout time.Duration
+	// StreamTimeout bounds StreamAllBooks instead of QueryTimeout, since a
+	// stream lasts as long as the client takes to read it and holds its
+	// connection until then.
+	StreamTimeout time.Duration
 }
 
-func (bookRepository *BookRepository) FindAllBooks() ([]domain.Book, error) {
-	rows, _ := bookRepository.DB.Query("SELECT id, title, price, published_date FROM books")
+func (bookRepository *BookRepository) FindAllBooks(ctx context.Context) ([]domain.Book, error) {
+	ctx, done := bookRepository.startQuery(ctx, "FindAllBooks")
+	defer done()
+
+	rows, err := bookRepository.DB.QueryContext(ctx, "SELECT id, title, price, published_date, updated_at FROM books")
+	if err != nil {
+		return nil, queryError(ctx, "find all books", err)
+	}
+	defer rows.Close()
+	return scanBooks(ctx, rows)
+}
+
+// StreamAllBooks calls visit for each book as it is read from the cursor, so
+// callers can write out the catalogue without holding it in memory. It stops
+// at the first error visit returns and returns that error as is.
+func (bookRepository *BookRepository) StreamAllBooks(ctx context.Context, visit func(domain.Book) error) error {
+	ctx, done := bookRepository.startQueryWithin(ctx, "StreamAllBooks", bookRepository.StreamTimeout)
+	defer done()
+
+	rows, err := bookRepository.DB.QueryContext(ctx, "SELECT id, title, price, published_date, updated_at FROM books")
+	if err != nil {
+		return queryError(ctx, "stream all books", err)
+	}
 	defer rows.Close()
+	for rows.Next() {
+		book := domain.Book{}
+		if err := rows.Scan(&book.ID, &book.Title, &book.Price, &book.PublishedDate, &book.UpdatedAt); err != nil {
+			return queryError(ctx, "scan book", err)
+		}
+		if err := visit(book); err != nil {
+			return err
+		}
+	}
+	return queryError(ctx, "read books", rows.Err())
+}
+
+func (bookRepository *BookRepository) FindBookByID(ctx context.Context, id int) (domain.Book, error) {
+	ctx, done := bookRepository.startQuery(ctx, "FindBookByID")
+	defer done()
+
+	book := domain.Book{}
+	err := bookRepository.DB.QueryRowContext(ctx, "SELECT id, title, price, published_date, updated_at FROM books WHERE id = $1", id).Scan(&book.ID, &book.Title, &book.Price, &book.PublishedDate, &book.UpdatedAt)
+	return book, queryError(ctx, fmt.Sprintf("find book %d", id), err)
+}
+
+// FindBooksByIDs returns the books that exist among ids, in no particular order.
+func (bookRepository *BookRepository) FindBooksByIDs(ctx context.Context, ids []int) ([]domain.Book, error) {
+	ctx, done := bookRepository.startQuery(ctx, "FindBooksByIDs")
+	defer done()
+
+	rows, err := bookRepository.DB.QueryContext(ctx,
+		"SELECT id, title, price, published_date, updated_at FROM books WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return nil, queryError(ctx, "find books by ids", err)
+	}
+	defer rows.Close()
+	return scanBooks(ctx, rows)
+}
+
+// SearchBooks returns one page of the books matching filter, ordered by ID,
+// together with the number of matching books across all pages.
+func (bookRepository *BookRepository) SearchBooks(ctx context.Context, filter BookFilter) ([]domain.Book, int, error) {
+	ctx, done := bookRepository.startQuery(ctx, "SearchBooks")
+	defer done()
+
+	where, args := filter.whereClause()
+	total := 0
+	if err := bookRepository.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM books"+where, args...).Scan(&total); err != nil {
+		return nil, 0, queryError(ctx, "count books", err)
+	}
 
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := bookRepository.DB.QueryContext(ctx,
+		"SELECT id, title, price, published_date, updated_at FROM books"+where+
+			" ORDER BY id LIMIT $"+strconv.Itoa(len(args)-1)+" OFFSET $"+strconv.Itoa(len(args)), args...)
+	if err != nil {
+		return nil, 0, queryError(ctx, "search books", err)
+	}
+	defer rows.Close()
+	books, err := scanBooks(ctx, rows)
+	return books, total, err
+}
+
+func (filter BookFilter) whereClause() (string, []interface{}) {
+	conditions, args := []string{}, []interface{}{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if filter.TitleContains != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.TitleContains)
+		add("title ILIKE '%' || ? || '%'", escaped)
+	}
+	if filter.MinPrice != nil {
+		add("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		add("price <= ?", *filter.MaxPrice)
+	}
+	if filter.PublishedAfter != "" {
+		add("published_date >= ?", filter.PublishedAfter)
+	}
+	if filter.PublishedBefore != "" {
+		add("published_date <= ?", filter.PublishedBefore)
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanBooks(ctx context.Context, rows *sql.Rows) ([]domain.Book, error) {
 	books := []domain.Book{}
 	for rows.Next() {
 		book := domain.Book{}
-		rows.Scan(&book.ID, &book.Title, &book.Price, &book.PublishedDate)
+		if err := rows.Scan(&book.ID, &book.Title, &book.Price, &book.PublishedDate, &book.UpdatedAt); err != nil {
+			return nil, queryError(ctx, "scan book", err)
+		}
 		books = append(books, book)
 	}
-	return books, nil
+	return books, queryError(ctx, "read books", rows.Err())
 }
 
-func (bookRepository *BookRepository) FindBookByID(id int) (domain.Book, error) {
-	book := domain.Book{}
-	err := bookRepository.DB.QueryRow("SELECT id, title, price, published_date FROM books WHERE id = $1", id).Scan(&book.ID, &book.Title, &book.Price, &book.PublishedDate)
-	return book, err
+func (bookRepository *BookRepository) SaveBook(ctx context.Context, book *domain.Book) error {
+	ctx, done := bookRepository.startQuery(ctx, "SaveBook")
+	defer done()
+
+	err := bookRepository.inTransaction(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx,
+			"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+			book.Title, book.Price, book.PublishedDate).Scan(&book.ID, &book.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, domain.EventBookCreated, book.ID, newBookEventPayload(*book))
+	})
+	return queryError(ctx, "save book", err)
 }
 
-func (bookRepository *BookRepository) SaveBook(book *domain.Book) error {
-	return bookRepository.DB.QueryRow(
-		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
-		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
+// UpdateBookTitle does nothing, and records no event, when the book does not
+// exist.
+func (bookRepository *BookRepository) UpdateBookTitle(ctx context.Context, id int, title string) error {
+	ctx, done := bookRepository.startQuery(ctx, "UpdateBookTitle")
+	defer done()
+
+	err := bookRepository.inTransaction(ctx, func(tx *sql.Tx) error {
+		book := domain.Book{}
+		err := tx.QueryRowContext(ctx,
+			"UPDATE books SET title = $1, updated_at = NOW() WHERE id = $2 RETURNING id, title, price, published_date, updated_at",
+			title, id).Scan(&book.ID, &book.Title, &book.Price, &book.PublishedDate, &book.UpdatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, domain.EventBookUpdated, id, newBookEventPayload(book))
+	})
+	return queryError(ctx, fmt.Sprintf("update title of book %d", id), err)
+}
+
+// DeleteBookByID does nothing, and records no event, when the book does not
+// exist.
+func (bookRepository *BookRepository) DeleteBookByID(ctx context.Context, id int) error {
+	ctx, done := bookRepository.startQuery(ctx, "DeleteBookByID")
+	defer done()
+
+	err := bookRepository.inTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM books WHERE id = $1", id)
+		if err != nil {
+			return err
+		}
+		if deleted, err := result.RowsAffected(); err != nil || deleted == 0 {
+			return err
+		}
+		return insertEvent(ctx, tx, domain.EventBookDeleted, id, map[string]int{"id": id})
+	})
+	return queryError(ctx, fmt.Sprintf("delete book %d", id), err)
 }
 
-func (bookRepository *BookRepository) UpdateBookTitle(id int, title string) error {
-	_, err := bookRepository.DB.Exec("UPDATE books SET title = $1 WHERE id = $2", title, id)
-	return err
+// inTransaction commits what write did only when it succeeds, so a change to
+// the books table and its outbox event are stored together or not at all.
+func (bookRepository *BookRepository) inTransaction(ctx context.Context, write func(tx *sql.Tx) error) error {
+	tx, err := bookRepository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := write(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
-func (bookRepository *BookRepository) DeleteBookByID(id int) error {
-	_, err := bookRepository.DB.Exec("DELETE FROM books WHERE id = $1", id)
-	return err
+// startQuery opens a span and applies the query deadline, returning a function
+// that releases both and records how long the query took.
+func (bookRepository *BookRepository) startQuery(ctx context.Context, method string) (context.Context, func()) {
+	return bookRepository.startQueryWithin(ctx, method, bookRepository.QueryTimeout)
+}
+
+func (bookRepository *BookRepository) startQueryWithin(ctx context.Context, method string, timeout time.Duration) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "BookRepository."+method)
+	span.SetAttribute("db.system", "postgresql")
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {
+		cancel()
+		span.End()
+		QueryDuration.WithLabelValues("book", method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// queryError names the failed operation and reports queries cut short by their
+// context as ErrQueryCanceled or ErrQueryTimeout so callers can tell them apart
+// from database failures.
+func queryError(ctx context.Context, operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	tracing.SpanFromContext(ctx).RecordError(err)
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%s: %w: %w", operation, ErrQueryCanceled, err)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s: %w: %w", operation, ErrQueryTimeout, err)
+	}
+	return fmt.Errorf("%s: %w", operation, err)
 }
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/eventbus"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// BookChangesChannel is the channel the trigger of
+// migrations/006_notify_book_changes.sql notifies of every books row written.
+const BookChangesChannel = "book_changes"
+
+// listenerPingInterval is how often an idle listener checks its connection, so
+// a silently dropped one is noticed and re-established.
+const listenerPingInterval = time.Minute
+
+type bookChangeNotification struct {
+	Type       string          `json:"type"`
+	BookID     int             `json:"book_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// BookChangeListener publishes the book changes the database announces on
+// BookChangesChannel to Bus, whichever instance made them. The connection is
+// re-established whenever it is lost; since changes made meanwhile were not
+// announced to it, Bus subscribers are then told to resync.
+type BookChangeListener struct {
+	ConnInfo             string
+	Bus                  *eventbus.Bus
+	MinReconnectInterval time.Duration
+	MaxReconnectInterval time.Duration
+}
+
+// Run listens until ctx is done. It returns an error only if the database
+// refuses to LISTEN.
+func (listener *BookChangeListener) Run(ctx context.Context) error {
+	pqListener := pq.NewListener(listener.ConnInfo, listener.MinReconnectInterval, listener.MaxReconnectInterval,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				slog.WarnContext(ctx, "book change listener connection failed", "error", err)
+			}
+		})
+	defer pqListener.Close()
+	stop := context.AfterFunc(ctx, func() { pqListener.Close() })
+	defer stop()
+
+	if err := pqListener.Listen(BookChangesChannel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	// Changes made before the listener connected were not announced to it.
+	listener.Bus.Resync()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification, open := <-pqListener.Notify:
+			if !open {
+				return nil
+			}
+			if notification == nil {
+				slog.WarnContext(ctx, "book change listener reconnected; changes made meanwhile were missed")
+				listener.Bus.Resync()
+				continue
+			}
+			event, err := parseBookChange(notification.Extra)
+			if err != nil {
+				slog.WarnContext(ctx, "ignoring malformed book change notification", "payload", notification.Extra, "error", err)
+				continue
+			}
+			listener.Bus.Publish(event)
+		case <-time.After(listenerPingInterval):
+			go pqListener.Ping()
+		}
+	}
+}
+
+func parseBookChange(payload string) (domain.Event, error) {
+	notification := bookChangeNotification{}
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		return domain.Event{}, err
+	}
+	if notification.Type == "" || notification.BookID == 0 {
+		return domain.Event{}, errors.New("book change notification without type or book id")
+	}
+	return domain.Event{Type: notification.Type, BookID: notification.BookID, Payload: notification.Data,
+		OccurredAt: notification.OccurredAt}, nil
+}
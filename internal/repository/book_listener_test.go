@@ -0,0 +1,94 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/eventbus"
+	"gojek/library-service-api/internal/repository"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// channelSubscriber passes what the bus delivers to channels, so tests can
+// wait for the listener's goroutine.
+type channelSubscriber struct {
+	events  chan domain.Event
+	resyncs chan struct{}
+}
+
+func newChannelSubscriber() *channelSubscriber {
+	return &channelSubscriber{events: make(chan domain.Event, 10), resyncs: make(chan struct{}, 10)}
+}
+
+func (subscriber *channelSubscriber) BookChanged(event domain.Event) {
+	subscriber.events <- event
+}
+
+func (subscriber *channelSubscriber) ChangesMissed() {
+	subscriber.resyncs <- struct{}{}
+}
+
+func (subscriber *channelSubscriber) nextEvent(t *testing.T) domain.Event {
+	t.Helper()
+	select {
+	case event := <-subscriber.events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("No book change was published")
+		return domain.Event{}
+	}
+}
+
+func TestBookChangeListener_GivenBookWritten_ThenPublishChangesOnBus(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := &eventbus.Bus{}
+	subscriber := newChannelSubscriber()
+	bus.Subscribe(subscriber)
+	listener := &repository.BookChangeListener{ConnInfo: "host=localhost user=postgres dbname=library sslmode=disable",
+		Bus: bus, MinReconnectInterval: 10 * time.Millisecond, MaxReconnectInterval: time.Second}
+	go listener.Run(ctx)
+
+	select {
+	case <-subscriber.resyncs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listener did not start listening")
+	}
+	id := 0
+	db.QueryRow("INSERT INTO books (title, price, published_date) VALUES ('Dune', 9.99, '1965-08-01') RETURNING id").Scan(&id)
+	db.Exec("DELETE FROM books WHERE id = $1", id)
+
+	created, deleted := subscriber.nextEvent(t), subscriber.nextEvent(t)
+	assert.Equal(t, domain.EventBookCreated, created.Type)
+	assert.Equal(t, id, created.BookID)
+	book := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(created.Payload, &book))
+	assert.Equal(t, "Dune", book["title"])
+	assert.Equal(t, "1965-08-01T00:00:00Z", book["publishedDate"])
+	assert.Equal(t, domain.EventBookDeleted, deleted.Type)
+	assert.JSONEq(t, `{"id":`+strconv.Itoa(id)+`}`, string(deleted.Payload))
+}
+
+func TestBookChangeListener_GivenUnreachableDatabaseAndCanceledContext_ThenReturn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := &repository.BookChangeListener{ConnInfo: "host=127.0.0.1 port=1 user=postgres sslmode=disable connect_timeout=1",
+		Bus: &eventbus.Bus{}, MinReconnectInterval: 10 * time.Millisecond, MaxReconnectInterval: 10 * time.Millisecond}
+	stopped := make(chan error)
+	go func() { stopped <- listener.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listener did not stop")
+	}
+}
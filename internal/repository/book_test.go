@@ -1,10 +1,14 @@
 package repository_test
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"gojek/library-service-api/internal/domain"
 	"gojek/library-service-api/internal/repository"
+	"gojek/library-service-api/internal/tracing"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
@@ -21,8 +25,64 @@ func setupTestDB(t *testing.T) *sql.DB {
             id SERIAL PRIMARY KEY,
             title VARCHAR(100),
             price NUMERIC(10, 2),
-            published_date DATE
+            published_date DATE,
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
         );
+        ALTER TABLE books ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+        CREATE TABLE IF NOT EXISTS outbox (
+            id BIGSERIAL PRIMARY KEY,
+            event_type VARCHAR(50) NOT NULL,
+            book_id INTEGER NOT NULL,
+            payload JSONB NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            published_at TIMESTAMPTZ,
+            attempts INTEGER NOT NULL DEFAULT 0,
+            last_error TEXT
+        );
+        CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+            id SERIAL PRIMARY KEY,
+            url TEXT NOT NULL,
+            secret TEXT NOT NULL,
+            events TEXT[] NOT NULL DEFAULT '{}',
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        );
+        CREATE TABLE IF NOT EXISTS webhook_deliveries (
+            id BIGSERIAL PRIMARY KEY,
+            subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+            event_id BIGINT NOT NULL,
+            event_type VARCHAR(50) NOT NULL,
+            payload TEXT NOT NULL,
+            status VARCHAR(20) NOT NULL DEFAULT 'pending',
+            attempts INTEGER NOT NULL DEFAULT 0,
+            next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            last_error TEXT NOT NULL DEFAULT '',
+            response_status INTEGER NOT NULL DEFAULT 0,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            UNIQUE (subscription_id, event_id)
+        );
+        CREATE OR REPLACE FUNCTION notify_book_change() RETURNS TRIGGER AS $$
+        BEGIN
+            IF TG_OP = 'DELETE' THEN
+                PERFORM pg_notify('book_changes', json_build_object(
+                    'type', 'BookDeleted', 'book_id', OLD.id, 'occurred_at', NOW(),
+                    'data', json_build_object('id', OLD.id))::text);
+            ELSE
+                PERFORM pg_notify('book_changes', json_build_object(
+                    'type', CASE TG_OP WHEN 'INSERT' THEN 'BookCreated' ELSE 'BookUpdated' END,
+                    'book_id', NEW.id, 'occurred_at', NOW(), 'data', json_build_object(
+                        'id', NEW.id, 'title', NEW.title, 'price', NEW.price,
+                        'publishedDate', to_char(NEW.published_date, 'YYYY-MM-DD"T00:00:00Z"'),
+                        'updatedAt', NEW.updated_at))::text);
+            END IF;
+            RETURN NULL;
+        END;
+        $$ LANGUAGE plpgsql;
+
+        DROP TRIGGER IF EXISTS books_notify_change ON books;
+        CREATE TRIGGER books_notify_change
+            AFTER INSERT OR UPDATE OR DELETE ON books
+            FOR EACH ROW EXECUTE FUNCTION notify_book_change();
     `)
 	if err != nil {
 		t.Fatalf("Failed to create books table: %v", err)
@@ -36,7 +96,7 @@ func TestFindAllBooks_GivenNothing_ThenReturnEmptyBooks(t *testing.T) {
 	defer db.Close()
 
 	bookRepository := &repository.BookRepository{DB: db}
-	books, err := bookRepository.FindAllBooks()
+	books, err := bookRepository.FindAllBooks(context.Background())
 	assert.NoError(t, err)
 	assert.IsType(t, []domain.Book{}, books)
 }
@@ -47,11 +107,11 @@ func TestFindAllBooks_GivenOneBook_ThenReturnListOfBooks(t *testing.T) {
 
 	book := &domain.Book{ID: 1, Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
 	db.QueryRow(
-		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
-		book.Title, book.Price, book.PublishedDate).Scan(&book.ID)
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		book.Title, book.Price, book.PublishedDate).Scan(&book.ID, &book.UpdatedAt)
 
 	bookRepository := &repository.BookRepository{DB: db}
-	books, _ := bookRepository.FindAllBooks()
+	books, _ := bookRepository.FindAllBooks(context.Background())
 	assert.Equal(t, []domain.Book{*book}, books)
 
 	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
@@ -62,7 +122,7 @@ func TestFindBookById_GivenNotFoundBook_ThenReturnError(t *testing.T) {
 	defer db.Close()
 
 	bookRepository := &repository.BookRepository{DB: db}
-	_, err := bookRepository.FindBookByID(1)
+	_, err := bookRepository.FindBookByID(context.Background(), 1)
 	assert.Error(t, err, sql.ErrNoRows)
 }
 
@@ -72,23 +132,63 @@ func TestFindBookById_GivenExistedBook_ThenReturnCorrespondingBook(t *testing.T)
 
 	createdBook := domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
 	db.QueryRow(
-		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
-		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID)
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID, &createdBook.UpdatedAt)
 
 	bookRepository := &repository.BookRepository{DB: db}
-	book, _ := bookRepository.FindBookByID(createdBook.ID)
+	book, _ := bookRepository.FindBookByID(context.Background(), createdBook.ID)
 	assert.Equal(t, createdBook, book)
 
 	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
 }
 
+func TestFindBooksByIDs_GivenExistedAndMissingIDs_ThenReturnOnlyExistedBooks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	createdBook := domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
+	db.QueryRow(
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID, &createdBook.UpdatedAt)
+
+	bookRepository := &repository.BookRepository{DB: db}
+	books, err := bookRepository.FindBooksByIDs(context.Background(), []int{createdBook.ID, -1})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Book{createdBook}, books)
+
+	db.Exec("DELETE FROM books WHERE id = $1", createdBook.ID)
+}
+
+func TestSearchBooks_GivenTitleFilterAndPageSize_ThenReturnPageAndTotal(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ids := []int{}
+	for _, title := range []string{"Clean Code", "Clean Architecture", "Refactoring"} {
+		id := 0
+		db.QueryRow("INSERT INTO books (title, price, published_date) VALUES ($1, 10, '2000-01-01') RETURNING id", title).Scan(&id)
+		ids = append(ids, id)
+	}
+
+	bookRepository := &repository.BookRepository{DB: db}
+	books, total, err := bookRepository.SearchBooks(context.Background(), repository.BookFilter{TitleContains: "clean", Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, books, 1)
+	assert.Equal(t, "Clean Code", books[0].Title)
+
+	for _, id := range ids {
+		db.Exec("DELETE FROM books WHERE id = $1", id)
+	}
+}
+
 func TestSaveBook_GivenNewBook_ThenNewBookInserted(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
 	book := &domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
 	bookRepository := &repository.BookRepository{DB: db}
-	err := bookRepository.SaveBook(book)
+	err := bookRepository.SaveBook(context.Background(), book)
 	assert.NoError(t, err)
 
 	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
@@ -100,11 +200,11 @@ func TestUpdateBookTitle_GivenUpdatedBookTitle_ThenReturnBookUpdated(t *testing.
 
 	createdBook := domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
 	db.QueryRow(
-		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
-		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID)
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID, &createdBook.UpdatedAt)
 
 	bookRepository := &repository.BookRepository{DB: db}
-	bookRepository.UpdateBookTitle(createdBook.ID, "Updated Book Title")
+	bookRepository.UpdateBookTitle(context.Background(), createdBook.ID, "Updated Book Title")
 	book := domain.Book{}
 	bookRepository.DB.QueryRow("SELECT title FROM books WHERE id = $1", createdBook.ID).Scan(&book.Title)
 	assert.Equal(t, "Updated Book Title", book.Title)
@@ -119,13 +219,143 @@ func TestDeleteBookById_GivenExistedBook_ThenCorrespondingBookDeleted(t *testing
 
 	createdBook := domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
 	db.QueryRow(
-		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id",
-		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID)
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		createdBook.Title, createdBook.Price, createdBook.PublishedDate).Scan(&createdBook.ID, &createdBook.UpdatedAt)
 
 	bookRepository := &repository.BookRepository{DB: db}
-	bookRepository.DeleteBookByID(createdBook.ID)
+	bookRepository.DeleteBookByID(context.Background(), createdBook.ID)
 
 	book := domain.Book{}
 	bookRepository.DB.QueryRow("SELECT id FROM books WHERE id = $1", createdBook.ID).Scan(&book.ID)
 	assert.Equal(t, 0, book.ID)
 }
+
+func TestFindAllBooks_GivenCanceledContext_ThenReturnQueryCanceledError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bookRepository := &repository.BookRepository{DB: db}
+	_, err := bookRepository.FindAllBooks(ctx)
+	assert.ErrorIs(t, err, repository.ErrQueryCanceled)
+}
+
+func TestFindBookById_GivenExpiredDeadline_ThenReturnQueryTimeoutError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	bookRepository := &repository.BookRepository{DB: db, QueryTimeout: time.Nanosecond}
+	_, err := bookRepository.FindBookByID(context.Background(), 1)
+	assert.ErrorIs(t, err, repository.ErrQueryTimeout)
+}
+
+func TestFindAllBooks_GivenQuery_ThenRecordQueryDuration(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bookRepository := &repository.BookRepository{DB: db}
+	bookRepository.FindAllBooks(ctx)
+
+	buffer := &bytes.Buffer{}
+	repository.QueryDuration.WriteText(buffer)
+	assert.Contains(t, buffer.String(), `db_query_duration_seconds_count{repository="book",method="FindAllBooks"}`)
+}
+
+func TestFindAllBooks_GivenFailedQuery_ThenRecordSpanWithError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+	exporter := &tracing.InMemoryExporter{}
+	tracing.SetTracer(&tracing.Tracer{Exporter: exporter})
+	defer tracing.SetTracer(&tracing.Tracer{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bookRepository := &repository.BookRepository{DB: db}
+	bookRepository.FindAllBooks(ctx)
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "BookRepository.FindAllBooks", spans[0].Name)
+	assert.NotEmpty(t, spans[0].Error)
+}
+
+func TestSearchBooks_GivenCanceledContext_ThenReturnQueryCanceledError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	minPrice := 10.0
+
+	bookRepository := &repository.BookRepository{DB: db}
+	_, _, err := bookRepository.SearchBooks(ctx, repository.BookFilter{TitleContains: "50%_off", MinPrice: &minPrice, Limit: 10})
+	assert.ErrorIs(t, err, repository.ErrQueryCanceled)
+}
+
+func TestStreamAllBooks_GivenOneBook_ThenVisitBook(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	book := &domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
+	db.QueryRow(
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		book.Title, book.Price, book.PublishedDate).Scan(&book.ID, &book.UpdatedAt)
+
+	bookRepository := &repository.BookRepository{DB: db}
+	visited := []domain.Book{}
+	err := bookRepository.StreamAllBooks(context.Background(), func(book domain.Book) error {
+		visited = append(visited, book)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, visited, *book)
+
+	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
+}
+
+func TestStreamAllBooks_GivenCanceledContext_ThenReturnQueryCanceledError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bookRepository := &repository.BookRepository{DB: db}
+	err := bookRepository.StreamAllBooks(ctx, func(domain.Book) error { return nil })
+	assert.ErrorIs(t, err, repository.ErrQueryCanceled)
+}
+
+func TestStreamAllBooks_GivenSlowVisitBeyondQueryTimeout_ThenVisitEveryBook(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	book := &domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01T00:00:00Z"}
+	db.QueryRow(
+		"INSERT INTO books (title, price, published_date) VALUES ($1, $2, $3) RETURNING id, updated_at",
+		book.Title, book.Price, book.PublishedDate).Scan(&book.ID, &book.UpdatedAt)
+
+	bookRepository := &repository.BookRepository{DB: db, QueryTimeout: 50 * time.Millisecond}
+	visited := []domain.Book{}
+	err := bookRepository.StreamAllBooks(context.Background(), func(book domain.Book) error {
+		time.Sleep(100 * time.Millisecond)
+		visited = append(visited, book)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, visited, *book)
+
+	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
+}
+
+func TestStreamAllBooks_GivenExpiredStreamTimeout_ThenReturnQueryTimeoutError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	bookRepository := &repository.BookRepository{DB: db, StreamTimeout: time.Nanosecond}
+	err := bookRepository.StreamAllBooks(context.Background(), func(domain.Book) error { return nil })
+	assert.ErrorIs(t, err, repository.ErrQueryTimeout)
+}
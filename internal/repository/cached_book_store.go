@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/domain"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// changedBooksQueueSize is how many changed books may wait for Run to drop
+// them from the cache; past that the cache is treated as having missed changes.
+const changedBooksQueueSize = 1024
+
+type clearableCache interface {
+	Clear(ctx context.Context) error
+}
+
+// CachedBookStore reads books by ID through Cache and drops the cached copy
+// after a write. Subscribed to the book event bus, it also drops the books
+// other replicas write, once Run is started. Cache failures are logged and the
+// lookup falls through to the wrapped store.
+type CachedBookStore struct {
+	BookStore
+	Cache         cache.Cache
+	invalidations atomic.Uint64
+	changedOnce   sync.Once
+	changed       chan int
+}
+
+// FindBookByID caches books that exist. A book read while an invalidation
+// arrived is returned but not cached, since it may already be stale.
+func (store *CachedBookStore) FindBookByID(ctx context.Context, id int) (domain.Book, error) {
+	key := bookCacheKey(id)
+	data, found, err := store.Cache.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read book cache", "key", key, "error", err)
+	}
+	book := domain.Book{}
+	if found && json.Unmarshal(data, &book) == nil {
+		return book, nil
+	}
+
+	invalidations := store.invalidations.Load()
+	book, err = store.BookStore.FindBookByID(ctx, id)
+	if err != nil || store.invalidations.Load() != invalidations {
+		return book, err
+	}
+	if data, err = json.Marshal(book); err == nil {
+		err = store.Cache.Set(ctx, key, data)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to write book cache", "key", key, "error", err)
+	}
+	return book, nil
+}
+
+func (store *CachedBookStore) UpdateBookTitle(ctx context.Context, id int, title string) error {
+	err := store.BookStore.UpdateBookTitle(ctx, id, title)
+	store.invalidate(ctx, id)
+	return err
+}
+
+func (store *CachedBookStore) DeleteBookByID(ctx context.Context, id int) error {
+	err := store.BookStore.DeleteBookByID(ctx, id)
+	store.invalidate(ctx, id)
+	return err
+}
+
+// BookChanged queues the book for Run to drop from the cache, since deleting it
+// may take a network round trip and bus subscribers must not block. Lookups
+// already running stop caching what they read straight away.
+func (store *CachedBookStore) BookChanged(event domain.Event) {
+	store.invalidations.Add(1)
+	select {
+	case store.changedBooks() <- event.BookID:
+	default:
+		slog.Warn("book cache invalidation queue is full", "book_id", event.BookID)
+		store.ChangesMissed()
+	}
+}
+
+// Run drops the books queued by BookChanged from the cache until ctx is done.
+func (store *CachedBookStore) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-store.changedBooks():
+			store.invalidate(ctx, id)
+		}
+	}
+}
+
+// ChangesMissed empties the cache when it can be emptied, which the memory
+// cache does without blocking. A shared Redis cache cannot be, so there the
+// books changed outside the service while the database connection was down
+// stay stale for up to their TTL; writes made through any instance still drop
+// their book themselves.
+func (store *CachedBookStore) ChangesMissed() {
+	store.invalidations.Add(1)
+	clearable, ok := store.Cache.(clearableCache)
+	if !ok {
+		return
+	}
+	if err := clearable.Clear(context.Background()); err != nil {
+		slog.Warn("failed to clear book cache", "error", err)
+	}
+}
+
+// invalidate runs after a write whether or not it succeeded, since a failed
+// write may still have been committed. It uses a context of its own so that a
+// canceled request cannot leave the old book cached.
+func (store *CachedBookStore) invalidate(ctx context.Context, id int) {
+	store.invalidations.Add(1)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	store.deleteCached(ctx, bookCacheKey(id))
+}
+
+func (store *CachedBookStore) deleteCached(ctx context.Context, key string) {
+	if err := store.Cache.Delete(ctx, key); err != nil {
+		slog.WarnContext(ctx, "failed to delete from book cache", "key", key, "error", err)
+	}
+}
+
+func (store *CachedBookStore) changedBooks() chan int {
+	store.changedOnce.Do(func() { store.changed = make(chan int, changedBooksQueueSize) })
+	return store.changed
+}
+
+func bookCacheKey(id int) string {
+	return "book:" + strconv.Itoa(id)
+}
@@ -0,0 +1,210 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/cache"
+	"gojek/library-service-api/internal/cache/resptest"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/eventbus"
+	"gojek/library-service-api/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubBookStore serves books from a map and counts lookups; methods the
+// tests do not need are left to the nil embedded BookStore.
+type stubBookStore struct {
+	repository.BookStore
+	books  map[int]domain.Book
+	finds  int
+	onFind func()
+}
+
+func (store *stubBookStore) FindBookByID(ctx context.Context, id int) (domain.Book, error) {
+	store.finds++
+	if store.onFind != nil {
+		store.onFind()
+	}
+	book, exists := store.books[id]
+	if !exists {
+		return domain.Book{}, sql.ErrNoRows
+	}
+	return book, nil
+}
+
+func (store *stubBookStore) UpdateBookTitle(ctx context.Context, id int, title string) error {
+	book := store.books[id]
+	book.Title = title
+	store.books[id] = book
+	return nil
+}
+
+func newStubBookStore() *stubBookStore {
+	updatedAt := time.Date(2026, 3, 1, 10, 30, 15, 0, time.UTC)
+	return &stubBookStore{books: map[int]domain.Book{
+		1: {ID: 1, Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01", UpdatedAt: updatedAt},
+	}}
+}
+
+func TestCachedBookStore_GivenRepeatedLookup_ThenQueryStoreOnce(t *testing.T) {
+	stub := newStubBookStore()
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: cache.NewMemoryCache(10, 0)}
+
+	first, err := bookStore.FindBookByID(context.Background(), 1)
+	assert.NoError(t, err)
+	second, err := bookStore.FindBookByID(context.Background(), 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, stub.books[1], first)
+	assert.Equal(t, stub.books[1], second)
+	assert.Equal(t, 1, stub.finds)
+}
+
+func TestCachedBookStore_GivenMissingBook_ThenDoNotCache(t *testing.T) {
+	stub := newStubBookStore()
+	bookCache := cache.NewMemoryCache(10, 0)
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: bookCache}
+
+	_, err := bookStore.FindBookByID(context.Background(), 2)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Equal(t, 0, bookCache.Stats().Entries)
+}
+
+func TestCachedBookStore_GivenUpdate_ThenServeNewTitle(t *testing.T) {
+	stub := newStubBookStore()
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: cache.NewMemoryCache(10, 0)}
+	bookStore.FindBookByID(context.Background(), 1)
+
+	assert.NoError(t, bookStore.UpdateBookTitle(context.Background(), 1, "Updated Book Title"))
+	book, _ := bookStore.FindBookByID(context.Background(), 1)
+
+	assert.Equal(t, "Updated Book Title", book.Title)
+	assert.Equal(t, 2, stub.finds)
+}
+
+func TestCachedBookStore_GivenInvalidationDuringLookup_ThenDoNotCacheStaleBook(t *testing.T) {
+	stub := newStubBookStore()
+	bookCache := cache.NewMemoryCache(10, 0)
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: bookCache}
+	stub.onFind = func() {
+		stub.onFind = nil
+		bookStore.UpdateBookTitle(context.Background(), 1, "Updated Book Title")
+	}
+
+	bookStore.FindBookByID(context.Background(), 1)
+
+	assert.Equal(t, 0, bookCache.Stats().Entries)
+}
+
+func TestCachedBookStore_GivenUnreachableCache_ThenFallBackToStore(t *testing.T) {
+	server := resptest.NewServer()
+	server.Close()
+	stub := newStubBookStore()
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: cache.NewRedisCache(server.Addr, "", 0)}
+
+	book, err := bookStore.FindBookByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.books[1], book)
+	assert.NoError(t, bookStore.UpdateBookTitle(context.Background(), 1, "Updated Book Title"))
+}
+
+func TestCachedBookStore_GivenSharedRedisCache_ThenReplicasSeeUpdates(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	stub := newStubBookStore()
+	replicaA := &repository.CachedBookStore{BookStore: stub, Cache: cache.NewRedisCache(server.Addr, "", time.Minute)}
+	replicaB := &repository.CachedBookStore{BookStore: stub, Cache: cache.NewRedisCache(server.Addr, "", time.Minute)}
+
+	replicaA.FindBookByID(context.Background(), 1)
+	replicaB.FindBookByID(context.Background(), 1)
+	assert.Equal(t, 1, stub.finds)
+
+	replicaA.UpdateBookTitle(context.Background(), 1, "Updated Book Title")
+	book, _ := replicaB.FindBookByID(context.Background(), 1)
+	assert.Equal(t, "Updated Book Title", book.Title)
+}
+
+func TestCachedBookStore_GivenBookChangedOnBus_ThenDropCachedBook(t *testing.T) {
+	stub := newStubBookStore()
+	memoryCache := cache.NewMemoryCache(10, 0)
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: memoryCache}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bookStore.Run(ctx)
+	bus := &eventbus.Bus{}
+	bus.Subscribe(bookStore)
+	bookStore.FindBookByID(context.Background(), 1)
+
+	stub.UpdateBookTitle(context.Background(), 1, "Updated Book Title")
+	bus.Publish(domain.Event{Type: domain.EventBookUpdated, BookID: 1})
+
+	assert.Eventually(t, func() bool { return memoryCache.Stats().Entries == 0 }, time.Second, time.Millisecond)
+	book, _ := bookStore.FindBookByID(context.Background(), 1)
+	assert.Equal(t, "Updated Book Title", book.Title)
+	assert.Equal(t, 2, stub.finds)
+}
+
+func TestCachedBookStore_GivenSlowCache_ThenBookChangedDoesNotBlock(t *testing.T) {
+	slowCache := &blockingCache{Cache: cache.NewMemoryCache(10, 0), release: make(chan struct{})}
+	defer close(slowCache.release)
+	bookStore := &repository.CachedBookStore{BookStore: newStubBookStore(), Cache: slowCache}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bookStore.Run(ctx)
+
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			bookStore.BookChanged(domain.Event{Type: domain.EventBookUpdated, BookID: 1})
+		}
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("BookChanged blocked on the cache")
+	}
+}
+
+// blockingCache holds every Delete until release is closed, as a hung shared
+// cache would.
+type blockingCache struct {
+	cache.Cache
+	release chan struct{}
+}
+
+func (blockingCache *blockingCache) Delete(ctx context.Context, key string) error {
+	<-blockingCache.release
+	return blockingCache.Cache.Delete(ctx, key)
+}
+
+func TestCachedBookStore_GivenChangesMissed_ThenClearMemoryCache(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(10, 0)
+	bookStore := &repository.CachedBookStore{BookStore: newStubBookStore(), Cache: memoryCache}
+	bookStore.FindBookByID(context.Background(), 1)
+
+	bookStore.ChangesMissed()
+
+	assert.Equal(t, 0, memoryCache.Stats().Entries)
+}
+
+func TestCachedBookStore_GivenChangesMissedDuringLookup_ThenDoNotCacheStaleBook(t *testing.T) {
+	stub := newStubBookStore()
+	server := resptest.NewServer()
+	defer server.Close()
+	bookStore := &repository.CachedBookStore{BookStore: stub, Cache: cache.NewRedisCache(server.Addr, "", time.Minute)}
+	stub.onFind = func() {
+		stub.onFind = nil
+		bookStore.ChangesMissed()
+	}
+
+	bookStore.FindBookByID(context.Background(), 1)
+	bookStore.FindBookByID(context.Background(), 1)
+
+	assert.Equal(t, 2, stub.finds)
+}
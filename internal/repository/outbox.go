@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gojek/library-service-api/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// outboxLockKey names the advisory lock held while relaying, so only one
+// replica relays at a time and events leave in the order they were written.
+const outboxLockKey = 0x6f7574626f78
+
+// bookEventPayload uses the field names of the book DTOs, so event consumers
+// see the same shape as API clients.
+type bookEventPayload struct {
+	ID            int       `json:"id"`
+	Title         string    `json:"title"`
+	Price         float64   `json:"price"`
+	PublishedDate string    `json:"publishedDate"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func newBookEventPayload(book domain.Book) bookEventPayload {
+	return bookEventPayload{ID: book.ID, Title: book.Title, Price: book.Price,
+		PublishedDate: book.PublishedDate, UpdatedAt: book.UpdatedAt}
+}
+
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType string, bookID int, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO outbox (event_type, book_id, payload) VALUES ($1, $2, $3)",
+		eventType, bookID, data)
+	return err
+}
+
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+// RelayEvents passes up to limit unpublished events to publish, oldest first,
+// and marks the ones it accepted as published. It stops at the first event
+// publish rejects, records the error against it and returns it together with
+// the number published before, so that event is retried first next time.
+// While another replica is relaying it returns straight away. An event can be
+// published again if the commit fails after publish accepted it.
+func (outboxRepository *OutboxRepository) RelayEvents(ctx context.Context, limit int, publish func(domain.Event) error) (int, error) {
+	tx, err := outboxRepository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, queryError(ctx, "begin relay", err)
+	}
+	defer tx.Rollback()
+
+	locked := false
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxLockKey).Scan(&locked); err != nil || !locked {
+		return 0, queryError(ctx, "lock outbox", err)
+	}
+	events, err := findUnpublishedEvents(ctx, tx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	published := []int64{}
+	var publishErr error
+	for _, event := range events {
+		if publishErr = publish(event); publishErr != nil {
+			_, err := tx.ExecContext(ctx, "UPDATE outbox SET attempts = attempts + 1, last_error = $1 WHERE id = $2",
+				publishErr.Error(), event.ID)
+			if err != nil {
+				return 0, queryError(ctx, "record failed event", err)
+			}
+			break
+		}
+		published = append(published, event.ID)
+	}
+	if len(published) > 0 {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE outbox SET published_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = ANY($1)",
+			pq.Array(published))
+		if err != nil {
+			return 0, queryError(ctx, "mark events published", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, queryError(ctx, "commit relay", err)
+	}
+	return len(published), publishErr
+}
+
+func findUnpublishedEvents(ctx context.Context, tx *sql.Tx, limit int) ([]domain.Event, error) {
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id, event_type, book_id, payload, created_at FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1",
+		limit)
+	if err != nil {
+		return nil, queryError(ctx, "find unpublished events", err)
+	}
+	defer rows.Close()
+	events := []domain.Event{}
+	for rows.Next() {
+		event := domain.Event{}
+		if err := rows.Scan(&event.ID, &event.Type, &event.BookID, &event.Payload, &event.OccurredAt); err != nil {
+			return nil, queryError(ctx, "scan event", err)
+		}
+		events = append(events, event)
+	}
+	return events, queryError(ctx, "read events", rows.Err())
+}
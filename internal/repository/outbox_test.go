@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveBook_GivenNewBook_ThenWriteBookCreatedEvent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	book := &domain.Book{Title: "Clean Code", Price: 15.99, PublishedDate: "1990-06-01"}
+	bookRepository := &repository.BookRepository{DB: db}
+	assert.NoError(t, bookRepository.SaveBook(context.Background(), book))
+
+	eventType, payload := "", ""
+	err := db.QueryRow("SELECT event_type, payload FROM outbox WHERE book_id = $1 ORDER BY id DESC LIMIT 1", book.ID).
+		Scan(&eventType, &payload)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventBookCreated, eventType)
+	assert.Contains(t, payload, `"title": "Clean Code"`)
+	assert.Contains(t, payload, `"publishedDate": `)
+
+	db.Exec("DELETE FROM books WHERE id = $1", book.ID)
+	db.Exec("DELETE FROM outbox WHERE book_id = $1", book.ID)
+}
+
+func TestDeleteBookById_GivenMissingBook_ThenWriteNoEvent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bookRepository := &repository.BookRepository{DB: db}
+	assert.NoError(t, bookRepository.DeleteBookByID(context.Background(), -1))
+
+	events := 0
+	db.QueryRow("SELECT COUNT(*) FROM outbox WHERE book_id = -1").Scan(&events)
+	assert.Equal(t, 0, events)
+}
+
+func TestRelayEvents_GivenPublishFailure_ThenKeepFailedEventPending(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.Exec("UPDATE outbox SET published_at = NOW() WHERE published_at IS NULL")
+
+	ids := make([]int64, 2)
+	for i := range ids {
+		db.QueryRow("INSERT INTO outbox (event_type, book_id, payload) VALUES ($1, $2, '{}') RETURNING id",
+			domain.EventBookUpdated, 42).Scan(&ids[i])
+	}
+
+	outboxRepository := &repository.OutboxRepository{DB: db}
+	published, err := outboxRepository.RelayEvents(context.Background(), 10, func(event domain.Event) error {
+		if event.ID == ids[1] {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, published)
+
+	var publishedAt sql.NullTime
+	lastError := ""
+	db.QueryRow("SELECT published_at, last_error FROM outbox WHERE id = $1", ids[1]).Scan(&publishedAt, &lastError)
+	assert.False(t, publishedAt.Valid)
+	assert.Equal(t, "broker unavailable", lastError)
+
+	db.Exec("DELETE FROM outbox WHERE book_id = 42")
+}
+
+func TestRelayEvents_GivenCanceledContext_ThenReturnQueryCanceledError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outboxRepository := &repository.OutboxRepository{DB: db}
+	_, err := outboxRepository.RelayEvents(ctx, 10, func(domain.Event) error { return nil })
+	assert.ErrorIs(t, err, repository.ErrQueryCanceled)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gojek/library-service-api/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const deliveryColumns = "id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, " +
+	"last_error, response_status, created_at, updated_at"
+
+// WebhookRepository implements webhook.Store on Postgres.
+type WebhookRepository struct {
+	DB *sql.DB
+}
+
+func (webhookRepository *WebhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	err := webhookRepository.DB.QueryRowContext(ctx,
+		"INSERT INTO webhook_subscriptions (url, secret, events) VALUES ($1, $2, $3) RETURNING id, created_at",
+		subscription.URL, subscription.Secret, pq.Array(subscription.Events)).Scan(&subscription.ID, &subscription.CreatedAt)
+	return queryError(ctx, "create webhook subscription", err)
+}
+
+func (webhookRepository *WebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	rows, err := webhookRepository.DB.QueryContext(ctx,
+		"SELECT id, url, secret, events, created_at FROM webhook_subscriptions ORDER BY id")
+	if err != nil {
+		return nil, queryError(ctx, "list webhook subscriptions", err)
+	}
+	defer rows.Close()
+	subscriptions := []domain.WebhookSubscription{}
+	for rows.Next() {
+		subscription := domain.WebhookSubscription{}
+		if err := rows.Scan(&subscription.ID, &subscription.URL, &subscription.Secret,
+			pq.Array(&subscription.Events), &subscription.CreatedAt); err != nil {
+			return nil, queryError(ctx, "scan webhook subscription", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, queryError(ctx, "read webhook subscriptions", rows.Err())
+}
+
+func (webhookRepository *WebhookRepository) FindSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	subscription := domain.WebhookSubscription{}
+	err := webhookRepository.DB.QueryRowContext(ctx,
+		"SELECT id, url, secret, events, created_at FROM webhook_subscriptions WHERE id = $1", id).
+		Scan(&subscription.ID, &subscription.URL, &subscription.Secret, pq.Array(&subscription.Events), &subscription.CreatedAt)
+	return subscription, queryError(ctx, fmt.Sprintf("find webhook subscription %d", id), err)
+}
+
+func (webhookRepository *WebhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	result, err := webhookRepository.DB.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err == nil {
+		if deleted, _ := result.RowsAffected(); deleted == 0 {
+			err = sql.ErrNoRows
+		}
+	}
+	return queryError(ctx, fmt.Sprintf("delete webhook subscription %d", id), err)
+}
+
+func (webhookRepository *WebhookRepository) EnqueueDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	tx, err := webhookRepository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return queryError(ctx, "begin enqueue webhook deliveries", err)
+	}
+	defer tx.Rollback()
+	for _, delivery := range deliveries {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at) "+
+				"VALUES ($1, $2, $3, $4, $5) ON CONFLICT (subscription_id, event_id) DO NOTHING",
+			delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload), delivery.NextAttemptAt)
+		if err != nil {
+			return queryError(ctx, "enqueue webhook delivery", err)
+		}
+	}
+	return queryError(ctx, "commit webhook deliveries", tx.Commit())
+}
+
+func (webhookRepository *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := webhookRepository.DB.QueryContext(ctx,
+		"UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id IN ("+
+			"SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= $1 "+
+			"ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED) RETURNING "+deliveryColumns,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, queryError(ctx, "claim webhook deliveries", err)
+	}
+	defer rows.Close()
+	return scanDeliveries(ctx, rows)
+}
+
+func (webhookRepository *WebhookRepository) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	_, err := webhookRepository.DB.ExecContext(ctx,
+		"UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, "+
+			"response_status = $6, updated_at = NOW() WHERE id = $1",
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError, delivery.ResponseStatus)
+	return queryError(ctx, fmt.Sprintf("update webhook delivery %d", delivery.ID), err)
+}
+
+func (webhookRepository *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := webhookRepository.DB.QueryContext(ctx,
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE subscription_id = $1 AND ($2 = '' OR status = $2) "+
+			"ORDER BY id DESC LIMIT $3",
+		subscriptionID, status, limit)
+	if err != nil {
+		return nil, queryError(ctx, "list webhook deliveries", err)
+	}
+	defer rows.Close()
+	return scanDeliveries(ctx, rows)
+}
+
+func scanDeliveries(ctx context.Context, rows *sql.Rows) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery := domain.WebhookDelivery{}
+		if err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType,
+			&delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError,
+			&delivery.ResponseStatus, &delivery.CreatedAt, &delivery.UpdatedAt); err != nil {
+			return nil, queryError(ctx, "scan webhook delivery", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, queryError(ctx, "read webhook deliveries", rows.Err())
+}
@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnqueueDeliveries_GivenSameEventTwice_ThenStoreOneDelivery(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	webhookRepository := &repository.WebhookRepository{DB: db}
+	subscription := &domain.WebhookSubscription{URL: "https://partner.example/hooks", Secret: "top-secret-value",
+		Events: []string{domain.EventBookCreated}}
+	assert.NoError(t, webhookRepository.CreateSubscription(ctx, subscription))
+	defer webhookRepository.DeleteSubscription(ctx, subscription.ID)
+
+	delivery := domain.WebhookDelivery{SubscriptionID: subscription.ID, EventID: 7, EventType: domain.EventBookCreated,
+		Payload: []byte(`{"id":7}`), NextAttemptAt: time.Now()}
+	assert.NoError(t, webhookRepository.EnqueueDeliveries(ctx, []domain.WebhookDelivery{delivery}))
+	assert.NoError(t, webhookRepository.EnqueueDeliveries(ctx, []domain.WebhookDelivery{delivery}))
+
+	deliveries, err := webhookRepository.ListDeliveries(ctx, subscription.ID, "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, domain.DeliveryPending, deliveries[0].Status)
+	assert.Equal(t, `{"id":7}`, string(deliveries[0].Payload))
+}
+
+func TestClaimDueDeliveries_GivenClaimedDelivery_ThenHideItUntilLeaseEnds(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	webhookRepository := &repository.WebhookRepository{DB: db}
+	subscription := &domain.WebhookSubscription{URL: "https://partner.example/hooks", Secret: "top-secret-value"}
+	webhookRepository.CreateSubscription(ctx, subscription)
+	defer webhookRepository.DeleteSubscription(ctx, subscription.ID)
+	webhookRepository.EnqueueDeliveries(ctx, []domain.WebhookDelivery{{SubscriptionID: subscription.ID, EventID: 8,
+		EventType: domain.EventBookDeleted, Payload: []byte(`{"id":8}`), NextAttemptAt: now}})
+
+	claimed, err := webhookRepository.ClaimDueDeliveries(ctx, now, time.Minute, 100)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+	claimedAgain, _ := webhookRepository.ClaimDueDeliveries(ctx, now, time.Minute, 100)
+	assert.Empty(t, claimedAgain)
+}
+
+func TestDeleteSubscription_GivenMissingSubscription_ThenReturnErrNoRows(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	webhookRepository := &repository.WebhookRepository{DB: db}
+	err := webhookRepository.DeleteSubscription(context.Background(), -1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestListSubscriptions_GivenCanceledContext_ThenReturnQueryCanceledError(t *testing.T) {
+	db, _ := sql.Open("postgres", "host=localhost user=postgres dbname=library sslmode=disable")
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	webhookRepository := &repository.WebhookRepository{DB: db}
+	_, err := webhookRepository.ListSubscriptions(ctx)
+	assert.ErrorIs(t, err, repository.ErrQueryCanceled)
+}
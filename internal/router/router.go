@@ -0,0 +1,38 @@
+package router
+
+import (
+	"gojek/library-service-api/internal/middleware"
+	"net/http"
+)
+
+type Router struct {
+	mux    *http.ServeMux
+	chain  middleware.Middleware
+	routes []string
+}
+
+func New(middlewares ...middleware.Middleware) *Router {
+	return &Router{
+		mux:   http.NewServeMux(),
+		chain: middleware.Chain(middlewares...),
+	}
+}
+
+// HandleFunc registers handler behind the router's middleware chain followed by
+// any route-specific middlewares, with the pattern made available to the
+// middlewares as the request's route.
+func (router *Router) HandleFunc(pattern string, handler http.HandlerFunc, middlewares ...middleware.Middleware) {
+	chained := router.chain(middleware.Chain(middlewares...)(handler))
+	router.mux.Handle(pattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chained.ServeHTTP(w, r.WithContext(middleware.WithRoute(r.Context(), pattern)))
+	}))
+	router.routes = append(router.routes, pattern)
+}
+
+func (router *Router) Routes() []string {
+	return router.routes
+}
+
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router.mux.ServeHTTP(w, r)
+}
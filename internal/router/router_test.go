@@ -0,0 +1,31 @@
+package router_test
+
+import (
+	"gojek/library-service-api/internal/middleware"
+	"gojek/library-service-api/internal/router"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleFunc_GivenRegisteredRoute_ThenHandlerSeesRoutePatternThroughChain(t *testing.T) {
+	chained := false
+	appRouter := router.New(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chained = true
+			next.ServeHTTP(w, r)
+		})
+	})
+	route := ""
+	appRouter.HandleFunc("/books/", func(w http.ResponseWriter, r *http.Request) {
+		route = middleware.RouteFromContext(r.Context())
+	})
+
+	appRouter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/books/1", nil))
+
+	assert.True(t, chained)
+	assert.Equal(t, "/books/", route)
+	assert.Equal(t, []string{"/books/"}, appRouter.Routes())
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertificateReloader serves a certificate/key pair from disk and reloads it
+// when either file's modification time changes, checking at most once per
+// interval so handshakes do not stat the files every time.
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	checkedAt   time.Time
+	now         func() time.Time
+}
+
+func NewCertificateReloader(certFile, keyFile string, interval time.Duration) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{certFile: certFile, keyFile: keyFile, interval: interval, now: time.Now}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (reloader *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+
+	if now := reloader.now(); now.Sub(reloader.checkedAt) >= reloader.interval {
+		reloader.checkedAt = now
+		if reloader.changed() {
+			if err := reloader.reload(); err != nil {
+				slog.Error("failed to reload tls certificate, keeping the previous one", "error", err)
+			}
+		}
+	}
+	return reloader.certificate, nil
+}
+
+func (reloader *CertificateReloader) changed() bool {
+	certInfo, certErr := os.Stat(reloader.certFile)
+	keyInfo, keyErr := os.Stat(reloader.keyFile)
+	if certErr != nil || keyErr != nil {
+		return false
+	}
+	return !certInfo.ModTime().Equal(reloader.certModTime) || !keyInfo.ModTime().Equal(reloader.keyModTime)
+}
+
+func (reloader *CertificateReloader) reload() error {
+	certInfo, err := os.Stat(reloader.certFile)
+	if err != nil {
+		return fmt.Errorf("stat certificate: %w", err)
+	}
+	keyInfo, err := os.Stat(reloader.keyFile)
+	if err != nil {
+		return fmt.Errorf("stat key: %w", err)
+	}
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair: %w", err)
+	}
+
+	reloader.certificate = &certificate
+	reloader.certModTime, reloader.keyModTime = certInfo.ModTime(), keyInfo.ModTime()
+	slog.Info("loaded tls certificate", "cert_file", reloader.certFile)
+	return nil
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"gojek/library-service-api/internal/config"
+	"os"
+)
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":    tls.NoClientCert,
+	"request": tls.VerifyClientCertIfGiven,
+	"require": tls.RequireAndVerifyClientCert,
+}
+
+// NewTLSConfig builds the server TLS configuration. Client certificates are
+// verified against the CA bundle when TLS_CLIENT_AUTH is "request" or "require".
+func NewTLSConfig(tlsConfig config.TLSConfig) (*tls.Config, error) {
+	reloader, err := NewCertificateReloader(tlsConfig.CertFile, tlsConfig.KeyFile, tlsConfig.ReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth, exists := clientAuthTypes[tlsConfig.ClientAuth]
+	if !exists {
+		return nil, fmt.Errorf("unknown tls client auth %q", tlsConfig.ClientAuth)
+	}
+
+	serverTLSConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+		ClientAuth:     clientAuth,
+	}
+	if clientAuth == tls.NoClientCert {
+		return serverTLSConfig, nil
+	}
+
+	if tlsConfig.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls client auth %q requires a client ca file", tlsConfig.ClientAuth)
+	}
+	caBundle, err := os.ReadFile(tlsConfig.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client ca file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no certificates found in client ca file %s", tlsConfig.ClientCAFile)
+	}
+	serverTLSConfig.ClientCAs = clientCAs
+	return serverTLSConfig, nil
+}
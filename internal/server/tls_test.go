@@ -0,0 +1,159 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"gojek/library-service-api/internal/auth"
+	"gojek/library-service-api/internal/config"
+	"gojek/library-service-api/internal/server"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	privateKey  *ecdsa.PrivateKey
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+func newTestCertificate(t *testing.T, template *x509.Certificate, issuer *testCertificate) *testCertificate {
+	privateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parent, signer := template, privateKey
+	if issuer != nil {
+		parent, signer = issuer.certificate, issuer.privateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &privateKey.PublicKey, signer)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	certificate, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(privateKey)
+	return &testCertificate{
+		certificate: certificate,
+		privateKey:  privateKey,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func newTestCA(t *testing.T) *testCertificate {
+	return newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Library Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+}
+
+func writeFile(t *testing.T, directory, name string, data []byte) string {
+	path := filepath.Join(directory, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestNewTLSConfig_GivenRequiredClientAuth_ThenExposeVerifiedClientIdentity(t *testing.T) {
+	directory := t.TempDir()
+	ca := newTestCA(t)
+	serverCertificate := newTestCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "127.0.0.1"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	clientCertificate := newTestCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "billing-service", OrganizationalUnit: []string{"librarian"}},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	serverTLSConfig, err := server.NewTLSConfig(config.TLSConfig{
+		CertFile:     writeFile(t, directory, "server.crt", serverCertificate.certPEM),
+		KeyFile:      writeFile(t, directory, "server.key", serverCertificate.keyPEM),
+		ClientCAFile: writeFile(t, directory, "ca.crt", ca.certPEM),
+		ClientAuth:   "require",
+	})
+	assert.NoError(t, err)
+
+	principal := auth.Principal{}
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = (&auth.Authenticator{}).Authenticate(r)
+	}))
+	testServer.TLS = serverTLSConfig
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.certificate)
+	clientKeyPair, _ := tls.X509KeyPair(clientCertificate.certPEM, clientCertificate.keyPEM)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      rootCAs,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{clientKeyPair},
+	}}}
+
+	res, err := client.Get(testServer.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	res.Body.Close()
+	assert.Equal(t, auth.Principal{Subject: "billing-service", Roles: []string{"librarian"}, Method: "mtls"}, principal)
+
+	anonymousClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs, ServerName: "localhost"}}}
+	_, err = anonymousClient.Get(testServer.URL)
+	assert.Error(t, err)
+}
+
+func TestNewTLSConfig_GivenClientAuthWithoutCA_ThenReturnError(t *testing.T) {
+	directory := t.TempDir()
+	serverCertificate := newTestCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "localhost"}}, newTestCA(t))
+
+	_, err := server.NewTLSConfig(config.TLSConfig{
+		CertFile:   writeFile(t, directory, "server.crt", serverCertificate.certPEM),
+		KeyFile:    writeFile(t, directory, "server.key", serverCertificate.keyPEM),
+		ClientAuth: "require",
+	})
+
+	assert.Error(t, err)
+}
+
+func TestGetCertificate_GivenRotatedFiles_ThenServeNewCertificate(t *testing.T) {
+	directory := t.TempDir()
+	ca := newTestCA(t)
+	oldCertificate := newTestCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "old"}}, ca)
+	newCertificate := newTestCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "new"}}, ca)
+	certFile := writeFile(t, directory, "server.crt", oldCertificate.certPEM)
+	keyFile := writeFile(t, directory, "server.key", oldCertificate.keyPEM)
+
+	reloader, err := server.NewCertificateReloader(certFile, keyFile, 0)
+	assert.NoError(t, err)
+
+	writeFile(t, directory, "server.crt", newCertificate.certPEM)
+	writeFile(t, directory, "server.key", newCertificate.keyPEM)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	os.Chtimes(keyFile, later, later)
+
+	certificate, err := reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	leaf, _ := x509.ParseCertificate(certificate.Certificate[0])
+	assert.Equal(t, "new", leaf.Subject.CommonName)
+}
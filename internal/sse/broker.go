@@ -0,0 +1,158 @@
+// Package sse fans book events out to Server-Sent Events streams and keeps
+// the latest of them so reconnecting clients can resume with Last-Event-ID.
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/outbox"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many events a stream may fall behind before it is
+// closed; its client then reconnects and catches up from the replay buffer.
+const subscriberBuffer = 64
+
+// ResyncEvent tells a resuming client that events it missed are no longer
+// buffered, so it should reload what it shows.
+const ResyncEvent = "resync"
+
+// Event is one SSE message. IDs are "<broker epoch>-<sequence>", so an ID
+// issued by another instance, or before a restart, is never mistaken for one
+// of this broker's.
+type Event struct {
+	ID       string
+	Type     string
+	Data     []byte
+	sequence uint64
+}
+
+// Broker keeps the latest events, as many as NewBroker is given, and
+// broadcasts new ones to every subscriber. It is an eventbus.Subscriber.
+type Broker struct {
+	mu          sync.Mutex
+	epoch       string
+	sequence    uint64
+	replay      []Event
+	replaySize  int
+	subscribers map[chan Event]struct{}
+}
+
+func NewBroker(replaySize int) *Broker {
+	return &Broker{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		replaySize:  replaySize,
+		subscribers: map[chan Event]struct{}{},
+	}
+}
+
+func (broker *Broker) BookChanged(event domain.Event) {
+	data, err := json.Marshal(outbox.NewMessage(event))
+	if err != nil {
+		slog.Warn("failed to encode book event", "type", event.Type, "book_id", event.BookID, "error", err)
+		return
+	}
+	broker.Broadcast(event.Type, data)
+}
+
+// ChangesMissed tells every stream to resync, including those that resume
+// from before this point later.
+func (broker *Broker) ChangesMissed() {
+	broker.Broadcast(ResyncEvent, []byte("{}"))
+}
+
+// Broadcast sends an event to every subscriber, closing the streams of those
+// that have fallen too far behind.
+func (broker *Broker) Broadcast(eventType string, data []byte) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.sequence++
+	event := Event{ID: broker.id(broker.sequence), Type: eventType, Data: data, sequence: broker.sequence}
+	broker.replay = append(broker.replay, event)
+	if len(broker.replay) > broker.replaySize {
+		broker.replay = broker.replay[len(broker.replay)-broker.replaySize:]
+	}
+	for events := range broker.subscribers {
+		select {
+		case events <- event:
+		default:
+			delete(broker.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Subscribe returns the buffered events after lastEventID and a channel of
+// the events broadcast from then on, which is closed when the subscriber falls
+// behind. When lastEventID is set but the buffer no longer reaches back to it,
+// the buffered events are replaced by a ResyncEvent. unsubscribe must be
+// called once the stream ends.
+func (broker *Broker) Subscribe(lastEventID string) (missed []Event, events <-chan Event, unsubscribe func()) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	missed, complete := broker.since(lastEventID)
+	if !complete {
+		missed = []Event{{ID: broker.id(broker.sequence), Type: ResyncEvent, Data: []byte("{}"), sequence: broker.sequence}}
+	}
+
+	subscription := make(chan Event, subscriberBuffer)
+	broker.subscribers[subscription] = struct{}{}
+	return missed, subscription, func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		if _, subscribed := broker.subscribers[subscription]; subscribed {
+			delete(broker.subscribers, subscription)
+			close(subscription)
+		}
+	}
+}
+
+// Subscribers returns the number of open streams.
+func (broker *Broker) Subscribers() int {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return len(broker.subscribers)
+}
+
+func (broker *Broker) since(lastEventID string) ([]Event, bool) {
+	if lastEventID == "" {
+		return nil, true
+	}
+	epoch, rawSequence, _ := strings.Cut(lastEventID, "-")
+	sequence, err := strconv.ParseUint(rawSequence, 10, 64)
+	if err != nil || epoch != broker.epoch || sequence > broker.sequence {
+		return nil, false
+	}
+	missed := []Event{}
+	for _, event := range broker.replay {
+		if event.sequence > sequence {
+			missed = append(missed, event)
+		}
+	}
+	oldest := broker.sequence - uint64(len(broker.replay)) + 1
+	return missed, sequence+1 >= oldest
+}
+
+func (broker *Broker) id(sequence uint64) string {
+	return broker.epoch + "-" + strconv.FormatUint(sequence, 10)
+}
+
+// WriteEvent frames event as an SSE message. Data must not contain newlines,
+// which compact JSON never does.
+func WriteEvent(w io.Writer, event Event) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+	return err
+}
+
+// WriteComment writes an SSE comment, which clients ignore; it keeps idle
+// connections from being closed by proxies.
+func WriteComment(w io.Writer, comment string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", comment)
+	return err
+}
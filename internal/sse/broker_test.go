@@ -0,0 +1,135 @@
+package sse_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/sse"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// broadcastEvents broadcasts count events and returns their IDs.
+func broadcastEvents(broker *sse.Broker, count int) []string {
+	_, events, unsubscribe := broker.Subscribe("")
+	defer unsubscribe()
+	ids := []string{}
+	for i := 1; i <= count; i++ {
+		broker.Broadcast(domain.EventBookUpdated, []byte(`{"id":`+strconv.Itoa(i)+`}`))
+		ids = append(ids, (<-events).ID)
+	}
+	return ids
+}
+
+func TestBookChanged_GivenSubscriber_ThenDeliverEventAsMessage(t *testing.T) {
+	broker := sse.NewBroker(10)
+	_, events, unsubscribe := broker.Subscribe("")
+	defer unsubscribe()
+
+	broker.BookChanged(domain.Event{Type: domain.EventBookCreated, BookID: 3, Payload: []byte(`{"id":3}`)})
+
+	event := <-events
+	assert.Equal(t, domain.EventBookCreated, event.Type)
+	message := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(event.Data, &message))
+	assert.Equal(t, float64(3), message["book_id"])
+	assert.NotContains(t, message, "id")
+}
+
+func TestChangesMissed_GivenEventIDBeforeIt_ThenReplayResync(t *testing.T) {
+	broker := sse.NewBroker(10)
+	ids := broadcastEvents(broker, 1)
+	broker.ChangesMissed()
+
+	missed, _, unsubscribe := broker.Subscribe(ids[0])
+	defer unsubscribe()
+
+	assert.Len(t, missed, 1)
+	assert.Equal(t, sse.ResyncEvent, missed[0].Type)
+}
+
+func TestSubscribe_GivenBufferedLastEventID_ThenReplayLaterEvents(t *testing.T) {
+	broker := sse.NewBroker(10)
+	ids := broadcastEvents(broker, 3)
+
+	missed, _, unsubscribe := broker.Subscribe(ids[0])
+	defer unsubscribe()
+
+	assert.Len(t, missed, 2)
+	assert.Equal(t, ids[1], missed[0].ID)
+	assert.Equal(t, ids[2], missed[1].ID)
+}
+
+func TestSubscribe_GivenLatestEventID_ThenReplayNothing(t *testing.T) {
+	broker := sse.NewBroker(10)
+	ids := broadcastEvents(broker, 2)
+
+	missed, _, unsubscribe := broker.Subscribe(ids[1])
+	defer unsubscribe()
+
+	assert.Empty(t, missed)
+}
+
+func TestSubscribe_GivenEventIDOlderThanBuffer_ThenSendResyncAtLatestEvent(t *testing.T) {
+	broker := sse.NewBroker(2)
+	ids := broadcastEvents(broker, 4)
+
+	missed, _, unsubscribe := broker.Subscribe(ids[0])
+	defer unsubscribe()
+
+	assert.Len(t, missed, 1)
+	assert.Equal(t, sse.ResyncEvent, missed[0].Type)
+	assert.Equal(t, ids[3], missed[0].ID)
+}
+
+func TestSubscribe_GivenEventIDFromAnotherBroker_ThenSendResync(t *testing.T) {
+	ids := broadcastEvents(sse.NewBroker(10), 1)
+	broker := sse.NewBroker(10)
+	broadcastEvents(broker, 1)
+
+	missed, _, unsubscribe := broker.Subscribe(ids[0])
+	defer unsubscribe()
+
+	assert.Len(t, missed, 1)
+	assert.Equal(t, sse.ResyncEvent, missed[0].Type)
+}
+
+func TestBroadcast_GivenSubscriberTooFarBehind_ThenCloseItsStream(t *testing.T) {
+	broker := sse.NewBroker(10)
+	_, events, unsubscribe := broker.Subscribe("")
+	defer unsubscribe()
+
+	for i := 0; i < 100; i++ {
+		broker.Broadcast(domain.EventBookUpdated, []byte(`{}`))
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Less(t, received, 100)
+	assert.Equal(t, 0, broker.Subscribers())
+}
+
+func TestUnsubscribe_GivenSubscriber_ThenRemoveIt(t *testing.T) {
+	broker := sse.NewBroker(10)
+	_, events, unsubscribe := broker.Subscribe("")
+
+	unsubscribe()
+	unsubscribe()
+
+	_, open := <-events
+	assert.False(t, open)
+	assert.Equal(t, 0, broker.Subscribers())
+}
+
+func TestWriteEvent_GivenEvent_ThenFrameAsSSEMessage(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	err := sse.WriteEvent(output, sse.Event{ID: "a-1", Type: domain.EventBookDeleted, Data: []byte(`{"id":3}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id: a-1\nevent: BookDeleted\ndata: {\"id\":3}\n\n", output.String())
+}
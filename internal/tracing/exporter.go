@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+type StdoutExporter struct {
+	mu     sync.Mutex
+	Writer io.Writer
+}
+
+func (exporter *StdoutExporter) Export(span SpanData) {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	json.NewEncoder(exporter.Writer).Encode(span)
+}
+
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (exporter *InMemoryExporter) Export(span SpanData) {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.spans = append(exporter.spans, span)
+}
+
+func (exporter *InMemoryExporter) Spans() []SpanData {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	return append([]SpanData{}, exporter.spans...)
+}
+
+func (exporter *InMemoryExporter) Reset() {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.spans = nil
+}
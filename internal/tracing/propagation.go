@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const TraceparentHeader = "traceparent"
+
+// Extract reads a W3C traceparent header into ctx so that spans started from it
+// continue the caller's trace.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	spanContext, ok := ParseTraceparent(header.Get(TraceparentHeader))
+	if !ok {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, spanContext)
+}
+
+// Inject writes the traceparent of the span carried by ctx, if any.
+func Inject(ctx context.Context, header http.Header) {
+	spanContext := SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, FormatTraceparent(spanContext))
+}
+
+func FormatTraceparent(spanContext SpanContext) string {
+	flags := "00"
+	if spanContext.Sampled {
+		flags = "01"
+	}
+	return "00-" + spanContext.TraceID.String() + "-" + spanContext.SpanID.String() + "-" + flags
+}
+
+func ParseTraceparent(traceparent string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, false
+	}
+
+	spanContext := SpanContext{}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(spanContext.TraceID) {
+		return SpanContext{}, false
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(spanContext.SpanID) {
+		return SpanContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return SpanContext{}, false
+	}
+
+	copy(spanContext.TraceID[:], traceID)
+	copy(spanContext.SpanID[:], spanID)
+	spanContext.Sampled = flags[0]&0x01 == 0x01
+	if !spanContext.IsValid() {
+		return SpanContext{}, false
+	}
+	return spanContext, true
+}
@@ -0,0 +1,44 @@
+package tracing_test
+
+import (
+	"context"
+	"gojek/library-service-api/internal/tracing"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent_GivenValidHeader_ThenReturnSpanContext(t *testing.T) {
+	spanContext, ok := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", spanContext.SpanID.String())
+	assert.True(t, spanContext.Sampled)
+}
+
+func TestParseTraceparent_GivenInvalidHeader_ThenReturnNotOk(t *testing.T) {
+	invalidHeaders := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-not-hex-01",
+	}
+	for _, header := range invalidHeaders {
+		_, ok := tracing.ParseTraceparent(header)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestInject_GivenExtractedContext_ThenRoundTripTraceparent(t *testing.T) {
+	incoming := http.Header{}
+	incoming.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	outgoing := http.Header{}
+	tracing.Inject(tracing.Extract(context.Background(), incoming), outgoing)
+
+	assert.Equal(t, incoming.Get(tracing.TraceparentHeader), outgoing.Get(tracing.TraceparentHeader))
+}
@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+type SpanID [8]byte
+
+func (traceID TraceID) String() string {
+	return hex.EncodeToString(traceID[:])
+}
+
+func (spanID SpanID) String() string {
+	return hex.EncodeToString(spanID[:])
+}
+
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (spanContext SpanContext) IsValid() bool {
+	return spanContext.TraceID != TraceID{} && spanContext.SpanID != SpanID{}
+}
+
+// SpanData is the finished span handed to exporters.
+type SpanData struct {
+	Name         string            `json:"name"`
+	TraceID      string            `json:"traceId"`
+	SpanID       string            `json:"spanId"`
+	ParentSpanID string            `json:"parentSpanId,omitempty"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+type Exporter interface {
+	Export(span SpanData)
+}
+
+type Span struct {
+	mu       sync.Mutex
+	tracer   *Tracer
+	context  SpanContext
+	data     SpanData
+	finished bool
+}
+
+type Tracer struct {
+	Exporter Exporter
+}
+
+var (
+	globalTracerMu sync.RWMutex
+	globalTracer   = &Tracer{}
+)
+
+func SetTracer(tracer *Tracer) {
+	globalTracerMu.Lock()
+	defer globalTracerMu.Unlock()
+	globalTracer = tracer
+}
+
+func GetTracer() *Tracer {
+	globalTracerMu.RLock()
+	defer globalTracerMu.RUnlock()
+	return globalTracer
+}
+
+// Start starts a span on the global tracer as a child of the span or remote
+// span context carried by ctx.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return GetTracer().Start(ctx, name)
+}
+
+func (tracer *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+	spanContext := SpanContext{TraceID: parent.TraceID, Sampled: true}
+	if parent.IsValid() {
+		spanContext.Sampled = parent.Sampled
+	} else {
+		rand.Read(spanContext.TraceID[:])
+	}
+	rand.Read(spanContext.SpanID[:])
+
+	span := &Span{
+		tracer:  tracer,
+		context: spanContext,
+		data: SpanData{
+			Name:       name,
+			TraceID:    spanContext.TraceID.String(),
+			SpanID:     spanContext.SpanID.String(),
+			Start:      time.Now(),
+			Attributes: map[string]string{},
+		},
+	}
+	if parent.IsValid() {
+		span.data.ParentSpanID = parent.SpanID.String()
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (span *Span) SpanContext() SpanContext {
+	if span == nil {
+		return SpanContext{}
+	}
+	return span.context
+}
+
+func (span *Span) SetAttribute(key, value string) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.data.Attributes[key] = value
+}
+
+func (span *Span) RecordError(err error) {
+	if span == nil || err == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.data.Error = err.Error()
+}
+
+func (span *Span) End() {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	if span.finished {
+		span.mu.Unlock()
+		return
+	}
+	span.finished = true
+	span.data.End = time.Now()
+	data := span.data
+	span.mu.Unlock()
+
+	if span.tracer.Exporter != nil && span.context.Sampled {
+		span.tracer.Exporter.Export(data)
+	}
+}
+
+type spanKey struct{}
+
+type remoteSpanContextKey struct{}
+
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns the context of the current span, falling back
+// to a span context extracted from an incoming request.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.context
+	}
+	spanContext, _ := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	return spanContext
+}
+
+func ContextWithRemoteSpanContext(ctx context.Context, spanContext SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanContextKey{}, spanContext)
+}
@@ -0,0 +1,55 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"gojek/library-service-api/internal/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStart_GivenParentSpan_ThenChildSpanSharesTraceID(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := &tracing.Tracer{Exporter: exporter}
+
+	ctx, parent := tracer.Start(context.Background(), "GET /books")
+	_, child := tracer.Start(ctx, "BookRepository.FindAllBooks")
+	child.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "BookRepository.FindAllBooks", spans[0].Name)
+	assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Empty(t, spans[1].ParentSpanID)
+}
+
+func TestEnd_GivenRecordedErrorAndAttributes_ThenExportOnce(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := &tracing.Tracer{Exporter: exporter}
+
+	_, span := tracer.Start(context.Background(), "BookRepository.SaveBook")
+	span.SetAttribute("db.system", "postgresql")
+	span.RecordError(errors.New("connection refused"))
+	span.End()
+	span.End()
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "postgresql", spans[0].Attributes["db.system"])
+	assert.Equal(t, "connection refused", spans[0].Error)
+	assert.False(t, spans[0].End.Before(spans[0].Start))
+}
+
+func TestStart_GivenUnsampledRemoteParent_ThenSpanIsNotExported(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := &tracing.Tracer{Exporter: exporter}
+	remote, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	_, span := tracer.Start(tracing.ContextWithRemoteSpanContext(context.Background(), remote), "GET /books")
+	span.End()
+
+	assert.Empty(t, exporter.Spans())
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/outbox"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultSendTimeout bounds a delivery's request when Client has no
+	// timeout of its own.
+	defaultSendTimeout = 10 * time.Second
+	// deliveryRecordMargin is the time allowed per delivery, on top of its
+	// request, for looking up the subscription and recording the outcome.
+	deliveryRecordMargin = 30 * time.Second
+)
+
+// Dispatcher queues a delivery for every subscription that wants an event and
+// sends the queued deliveries. It is an outbox.Publisher, so events reach it
+// from the outbox relay.
+type Dispatcher struct {
+	Store       Store
+	Client      *http.Client
+	MaxAttempts int
+	// Failed attempts are retried after BaseDelay, doubling with every attempt
+	// up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	BatchSize int
+	Interval  time.Duration
+	Now       func() time.Time
+}
+
+func (dispatcher *Dispatcher) Publish(ctx context.Context, event domain.Event) error {
+	subscriptions, err := dispatcher.Store.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(outbox.NewMessage(event))
+	if err != nil {
+		return err
+	}
+	deliveries := []domain.WebhookDelivery{}
+	for _, subscription := range subscriptions {
+		if subscription.Wants(event.Type) {
+			deliveries = append(deliveries, domain.WebhookDelivery{SubscriptionID: subscription.ID,
+				EventID: event.ID, EventType: event.Type, Payload: payload, NextAttemptAt: dispatcher.now()})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return dispatcher.Store.EnqueueDeliveries(ctx, deliveries)
+}
+
+// Run sends due deliveries every Interval until ctx is done.
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	for {
+		if _, err := dispatcher.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "failed to deliver webhooks", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dispatcher.Interval):
+		}
+	}
+}
+
+// DeliverDue attempts the deliveries that are due until none are left, and
+// returns how many it attempted.
+func (dispatcher *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		deliveries, err := dispatcher.Store.ClaimDueDeliveries(ctx, dispatcher.now(), dispatcher.batchLease(), dispatcher.BatchSize)
+		if err != nil {
+			return attempted, err
+		}
+		for _, delivery := range deliveries {
+			if err := dispatcher.attempt(ctx, delivery); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+		if len(deliveries) < dispatcher.BatchSize {
+			return attempted, nil
+		}
+	}
+}
+
+func (dispatcher *Dispatcher) attempt(ctx context.Context, delivery domain.WebhookDelivery) error {
+	subscription, err := dispatcher.Store.FindSubscription(ctx, delivery.SubscriptionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus, err = dispatcher.send(ctx, subscription, delivery)
+	switch {
+	case err == nil:
+		delivery.Status, delivery.LastError = domain.DeliveryDelivered, ""
+	case delivery.Attempts >= dispatcher.MaxAttempts:
+		delivery.Status, delivery.LastError = domain.DeliveryDead, err.Error()
+		slog.WarnContext(ctx, "webhook delivery dead-lettered", "delivery_id", delivery.ID,
+			"subscription_id", subscription.ID, "event_id", delivery.EventID, "attempts", delivery.Attempts, "error", err)
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = dispatcher.now().Add(dispatcher.backoff(delivery.Attempts))
+	}
+	return dispatcher.Store.UpdateDelivery(ctx, delivery)
+}
+
+// batchLease is how long a claimed batch is hidden from other replicas. The
+// deliveries are sent one after another, so the lease covers every one of them
+// taking as long as it may; otherwise another replica could claim and send the
+// last ones again while they are still being sent.
+func (dispatcher *Dispatcher) batchLease() time.Duration {
+	return time.Duration(max(dispatcher.BatchSize, 1)) * (dispatcher.sendTimeout() + deliveryRecordMargin)
+}
+
+func (dispatcher *Dispatcher) sendTimeout() time.Duration {
+	if dispatcher.Client != nil && dispatcher.Client.Timeout > 0 {
+		return dispatcher.Client.Timeout
+	}
+	return defaultSendTimeout
+}
+
+// send POSTs the delivery and returns the response status, if any.
+func (dispatcher *Dispatcher) send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dispatcher.sendTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set("X-Event-ID", strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set("X-Event-Type", delivery.EventType)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, dispatcher.now(), delivery.Payload))
+
+	client := dispatcher.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+func (dispatcher *Dispatcher) backoff(attempts int) time.Duration {
+	delay := dispatcher.BaseDelay
+	for i := 1; i < attempts && delay < dispatcher.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, dispatcher.MaxDelay)
+}
+
+func (dispatcher *Dispatcher) now() time.Time {
+	if dispatcher.Now != nil {
+		return dispatcher.Now()
+	}
+	return time.Now()
+}
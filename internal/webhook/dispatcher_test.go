@@ -0,0 +1,199 @@
+package webhook_test
+
+import (
+	"context"
+	"gojek/library-service-api/internal/domain"
+	"gojek/library-service-api/internal/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receiver is a partner endpoint that answers with the queued statuses, then
+// 204, and verifies every signature.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+	verified []error
+}
+
+func newReceiver(secret string, now func() time.Time, statuses ...int) *receiver {
+	receiver := &receiver{statuses: statuses}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.bodies = append(receiver.bodies, string(body))
+		receiver.headers = append(receiver.headers, r.Header)
+		receiver.verified = append(receiver.verified,
+			webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, now(), time.Minute))
+		status := http.StatusNoContent
+		if len(receiver.statuses) > 0 {
+			status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return receiver
+}
+
+type clock struct {
+	now time.Time
+}
+
+func (clock *clock) Now() time.Time {
+	return clock.now
+}
+
+func newDispatcher(store webhook.Store, clock *clock) *webhook.Dispatcher {
+	return &webhook.Dispatcher{Store: store, MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute,
+		BatchSize: 10, Now: clock.Now}
+}
+
+var bookUpdated = domain.Event{ID: 11, Type: domain.EventBookUpdated, BookID: 3, Payload: []byte(`{"id":3,"title":"Dune"}`)}
+
+func TestDispatcher_GivenSubscribedReceiver_ThenDeliverSignedEvent(t *testing.T) {
+	clock := &clock{now: time.Now()}
+	receiver := newReceiver("top-secret-value", clock.Now)
+	defer receiver.Close()
+	store := webhook.NewMemoryStore()
+	subscription := &domain.WebhookSubscription{URL: receiver.URL, Secret: "top-secret-value"}
+	store.CreateSubscription(context.Background(), subscription)
+	dispatcher := newDispatcher(store, clock)
+
+	assert.NoError(t, dispatcher.Publish(context.Background(), bookUpdated))
+	attempted, err := dispatcher.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	assert.NoError(t, receiver.verified[0])
+	assert.Equal(t, "BookUpdated", receiver.headers[0].Get("X-Event-Type"))
+	assert.Equal(t, "11", receiver.headers[0].Get("X-Event-ID"))
+	assert.JSONEq(t, `{"id":11,"type":"BookUpdated","book_id":3,"occurred_at":"0001-01-01T00:00:00Z","data":{"id":3,"title":"Dune"}}`, receiver.bodies[0])
+	deliveries, _ := store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Equal(t, domain.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+}
+
+func TestDispatcher_GivenEventFilter_ThenSkipOtherEvents(t *testing.T) {
+	store := webhook.NewMemoryStore()
+	subscription := &domain.WebhookSubscription{URL: "http://partner.example", Events: []string{domain.EventBookCreated}}
+	store.CreateSubscription(context.Background(), subscription)
+
+	newDispatcher(store, &clock{now: time.Now()}).Publish(context.Background(), bookUpdated)
+
+	deliveries, _ := store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Empty(t, deliveries)
+}
+
+func TestDispatcher_GivenEventPublishedTwice_ThenDeliverOnce(t *testing.T) {
+	store := webhook.NewMemoryStore()
+	subscription := &domain.WebhookSubscription{URL: "http://partner.example"}
+	store.CreateSubscription(context.Background(), subscription)
+	dispatcher := newDispatcher(store, &clock{now: time.Now()})
+
+	dispatcher.Publish(context.Background(), bookUpdated)
+	dispatcher.Publish(context.Background(), bookUpdated)
+
+	deliveries, _ := store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestDispatcher_GivenFailingReceiver_ThenRetryWithExponentialBackoff(t *testing.T) {
+	clock := &clock{now: time.Now()}
+	receiver := newReceiver("top-secret-value", clock.Now, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	defer receiver.Close()
+	store := webhook.NewMemoryStore()
+	subscription := &domain.WebhookSubscription{URL: receiver.URL, Secret: "top-secret-value"}
+	store.CreateSubscription(context.Background(), subscription)
+	dispatcher := newDispatcher(store, clock)
+	dispatcher.Publish(context.Background(), bookUpdated)
+
+	dispatcher.DeliverDue(context.Background())
+	deliveries, _ := store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Equal(t, domain.DeliveryPending, deliveries[0].Status)
+	assert.Equal(t, clock.now.Add(time.Second), deliveries[0].NextAttemptAt)
+	assert.Equal(t, "receiver answered 503 Service Unavailable", deliveries[0].LastError)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	attempted, _ := dispatcher.DeliverDue(context.Background())
+	assert.Equal(t, 0, attempted)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	dispatcher.DeliverDue(context.Background())
+	deliveries, _ = store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Equal(t, clock.now.Add(2*time.Second), deliveries[0].NextAttemptAt)
+
+	clock.now = clock.now.Add(2 * time.Second)
+	dispatcher.DeliverDue(context.Background())
+	deliveries, _ = store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Equal(t, domain.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+}
+
+func TestDispatcher_GivenReceiverFailingEveryAttempt_ThenDeadLetterDelivery(t *testing.T) {
+	clock := &clock{now: time.Now()}
+	receiver := newReceiver("top-secret-value", clock.Now, http.StatusGone, http.StatusGone, http.StatusGone)
+	defer receiver.Close()
+	store := webhook.NewMemoryStore()
+	subscription := &domain.WebhookSubscription{URL: receiver.URL, Secret: "top-secret-value"}
+	store.CreateSubscription(context.Background(), subscription)
+	dispatcher := newDispatcher(store, clock)
+	dispatcher.Publish(context.Background(), bookUpdated)
+
+	for attempt := 0; attempt < 5; attempt++ {
+		dispatcher.DeliverDue(context.Background())
+		clock.now = clock.now.Add(time.Minute)
+	}
+
+	deadLetters, _ := store.ListDeliveries(context.Background(), subscription.ID, domain.DeliveryDead, 10)
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, http.StatusGone, deadLetters[0].ResponseStatus)
+	assert.Len(t, receiver.bodies, 3)
+}
+
+func TestDispatcher_GivenUnreachableReceiver_ThenRecordError(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+	store := webhook.NewMemoryStore()
+	subscription := &domain.WebhookSubscription{URL: receiver.URL}
+	store.CreateSubscription(context.Background(), subscription)
+	dispatcher := newDispatcher(store, &clock{now: time.Now()})
+	dispatcher.Publish(context.Background(), bookUpdated)
+
+	_, err := dispatcher.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	deliveries, _ := store.ListDeliveries(context.Background(), subscription.ID, "", 10)
+	assert.Contains(t, deliveries[0].LastError, "connection refused")
+	assert.Equal(t, 0, deliveries[0].ResponseStatus)
+}
+
+type leaseRecordingStore struct {
+	webhook.Store
+	leases []time.Duration
+}
+
+func (store *leaseRecordingStore) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	store.leases = append(store.leases, lease)
+	return store.Store.ClaimDueDeliveries(ctx, now, lease, limit)
+}
+
+func TestDispatcher_GivenBatchOfSlowDeliveries_ThenLeaseOutlastsTheWholeBatch(t *testing.T) {
+	store := &leaseRecordingStore{Store: webhook.NewMemoryStore()}
+	dispatcher := newDispatcher(store, &clock{now: time.Now()})
+	dispatcher.Client = &http.Client{Timeout: 10 * time.Second}
+
+	dispatcher.DeliverDue(context.Background())
+
+	assert.Len(t, store.leases, 1)
+	assert.GreaterOrEqual(t, store.leases[0], time.Duration(dispatcher.BatchSize)*dispatcher.Client.Timeout)
+}
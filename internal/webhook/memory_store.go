@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"gojek/library-service-api/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps subscriptions and deliveries in this process, for tests
+// and single-instance setups.
+type MemoryStore struct {
+	mu                 sync.Mutex
+	subscriptions      map[int]domain.WebhookSubscription
+	deliveries         map[int64]domain.WebhookDelivery
+	lastSubscriptionID int
+	lastDeliveryID     int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{subscriptions: map[int]domain.WebhookSubscription{}, deliveries: map[int64]domain.WebhookDelivery{}}
+}
+
+func (store *MemoryStore) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.lastSubscriptionID++
+	subscription.ID, subscription.CreatedAt = store.lastSubscriptionID, time.Now()
+	store.subscriptions[subscription.ID] = *subscription
+	return nil
+}
+
+func (store *MemoryStore) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	subscriptions := make([]domain.WebhookSubscription, 0, len(store.subscriptions))
+	for _, subscription := range store.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].ID < subscriptions[j].ID })
+	return subscriptions, nil
+}
+
+func (store *MemoryStore) FindSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	subscription, exists := store.subscriptions[id]
+	if !exists {
+		return domain.WebhookSubscription{}, sql.ErrNoRows
+	}
+	return subscription, nil
+}
+
+func (store *MemoryStore) DeleteSubscription(ctx context.Context, id int) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, exists := store.subscriptions[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(store.subscriptions, id)
+	for deliveryID, delivery := range store.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(store.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (store *MemoryStore) EnqueueDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, delivery := range deliveries {
+		if store.hasDelivery(delivery.SubscriptionID, delivery.EventID) {
+			continue
+		}
+		store.lastDeliveryID++
+		now := time.Now()
+		delivery.ID, delivery.Status, delivery.CreatedAt, delivery.UpdatedAt = store.lastDeliveryID, domain.DeliveryPending, now, now
+		if delivery.NextAttemptAt.IsZero() {
+			delivery.NextAttemptAt = now
+		}
+		store.deliveries[delivery.ID] = delivery
+	}
+	return nil
+}
+
+func (store *MemoryStore) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	due := []domain.WebhookDelivery{}
+	for _, delivery := range store.sortedDeliveries() {
+		if len(due) == limit {
+			break
+		}
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+			delivery.NextAttemptAt = now.Add(lease)
+			store.deliveries[delivery.ID] = delivery
+		}
+	}
+	return due, nil
+}
+
+func (store *MemoryStore) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, exists := store.deliveries[delivery.ID]; exists {
+		delivery.UpdatedAt = time.Now()
+		store.deliveries[delivery.ID] = delivery
+	}
+	return nil
+}
+
+func (store *MemoryStore) ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]domain.WebhookDelivery, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	deliveries := []domain.WebhookDelivery{}
+	sorted := store.sortedDeliveries()
+	for i := len(sorted) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if sorted[i].SubscriptionID == subscriptionID && (status == "" || sorted[i].Status == status) {
+			deliveries = append(deliveries, sorted[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (store *MemoryStore) hasDelivery(subscriptionID int, eventID int64) bool {
+	for _, delivery := range store.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (store *MemoryStore) sortedDeliveries() []domain.WebhookDelivery {
+	deliveries := make([]domain.WebhookDelivery, 0, len(store.deliveries))
+	for _, delivery := range store.deliveries {
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	return deliveries
+}
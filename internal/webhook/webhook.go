@@ -0,0 +1,92 @@
+// Package webhook delivers book events to the URLs partners subscribed, with
+// signed requests, retries with exponential backoff and dead letters.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gojek/library-service-api/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SignatureHeader = "X-Webhook-Signature"
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Store keeps subscriptions and their deliveries. Missing subscriptions are
+// reported as sql.ErrNoRows, as the repositories do.
+type Store interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	FindSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error)
+	// DeleteSubscription also deletes the subscription's deliveries.
+	DeleteSubscription(ctx context.Context, id int) error
+	// EnqueueDeliveries skips deliveries of an event the subscription already
+	// has, so an event published twice is delivered once.
+	EnqueueDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now and
+	// moves their next attempt to now+lease, so other replicas leave them alone
+	// while they are attempted.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	// ListDeliveries returns the subscription's latest deliveries, newest
+	// first, limited to those in status unless it is empty.
+	ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// NewSecret returns a random signing secret for subscriptions created without
+// one.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// Sign returns the SignatureHeader value for body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">". Signing the
+// timestamp lets receivers reject replayed requests.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + signature(secret, unix, body)
+}
+
+// Verify checks a SignatureHeader value against body and rejects signatures
+// older than tolerance.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	unix, expected := "", ""
+	for _, part := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "t":
+			unix = value
+		case "v1":
+			expected = value
+		}
+	}
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || expected == "" {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(expected), []byte(signature(secret, unix, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signature(secret, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,32 @@
+package webhook_test
+
+import (
+	"gojek/library-service-api/internal/webhook"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var signedAt = time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+
+func TestSign_GivenBody_ThenReturnTimestampAndHMAC(t *testing.T) {
+	header := webhook.Sign("top-secret-value", signedAt, []byte(`{"id":1}`))
+
+	assert.Regexp(t, `^t=1772361000,v1=[0-9a-f]{64}$`, header)
+}
+
+func TestVerify_GivenSignedBody_ThenAccept(t *testing.T) {
+	header := webhook.Sign("top-secret-value", signedAt, []byte(`{"id":1}`))
+
+	assert.NoError(t, webhook.Verify("top-secret-value", header, []byte(`{"id":1}`), signedAt.Add(time.Minute), 5*time.Minute))
+}
+
+func TestVerify_GivenTamperedBodyWrongSecretOrOldSignature_ThenReject(t *testing.T) {
+	header := webhook.Sign("top-secret-value", signedAt, []byte(`{"id":1}`))
+
+	assert.ErrorIs(t, webhook.Verify("top-secret-value", header, []byte(`{"id":2}`), signedAt, time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("other-secret-value", header, []byte(`{"id":1}`), signedAt, time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("top-secret-value", header, []byte(`{"id":1}`), signedAt.Add(time.Hour), time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("top-secret-value", "v1=abc", []byte(`{"id":1}`), signedAt, time.Minute), webhook.ErrInvalidSignature)
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS books (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(100),
+    price NUMERIC(10, 2),
+    published_date DATE
+);
@@ -0,0 +1,22 @@
+CREATE OR REPLACE FUNCTION notify_book_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('book_changes', json_build_object(
+            'type', 'BookDeleted', 'book_id', OLD.id, 'occurred_at', NOW(),
+            'data', json_build_object('id', OLD.id))::text);
+    ELSE
+        PERFORM pg_notify('book_changes', json_build_object(
+            'type', CASE TG_OP WHEN 'INSERT' THEN 'BookCreated' ELSE 'BookUpdated' END,
+            'book_id', NEW.id, 'occurred_at', NOW(), 'data', json_build_object(
+                'id', NEW.id, 'title', NEW.title, 'price', NEW.price,
+                'publishedDate', to_char(NEW.published_date, 'YYYY-MM-DD"T00:00:00Z"'),
+                'updatedAt', NEW.updated_at))::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS books_notify_change ON books;
+CREATE TRIGGER books_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON books
+    FOR EACH ROW EXECUTE FUNCTION notify_book_change();